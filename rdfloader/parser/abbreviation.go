@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+
+	xmlreader "github.com/RishabhBhatnagar/gordf/rdfloader/xmlreader"
+)
+
+const (
+	parseTypeResource   = "Resource"
+	parseTypeCollection = "Collection"
+	parseTypeLiteral    = "Literal"
+)
+
+// pendingChild is a node element discovered while turning one subject's
+// properties into triples that itself still needs to be walked for its own
+// properties: a plain nested resource, the blank node implied by
+// rdf:parseType="Resource", or a member of an rdf:parseType="Collection".
+type pendingChild struct {
+	scope *NamespaceScope
+	block *xmlreader.Block
+	node  *Node
+	// propertyListOnly is set for the blank node implied by
+	// rdf:parseType="Resource": per parseTypeResourcePropertyElt, that node's
+	// properties still come from block's children, but block's own tag name
+	// (the property element's name) must not be asserted as its rdf:type.
+	propertyListOnly bool
+}
+
+// processPendingChild walks child through the rules appropriate to how it
+// was discovered: a plain node element asserts its own rdf:type before its
+// property list, while the implied blank node of rdf:parseType="Resource"
+// only gets its property list.
+func (parser *Parser) processPendingChild(child pendingChild, appendTriple func(*Triple)) ([]pendingChild, error) {
+	if child.propertyListOnly {
+		return parser.processPropertyList(child.scope, child.block, child.node, appendTriple)
+	}
+	return parser.processNodeElement(child.scope, child.block, child.node, appendTriple)
+}
+
+// xmlAttr looks up an attribute written with the reserved "xml" prefix -
+// xml:lang, xml:base - which the XML Namespaces spec binds to the XML
+// namespace without it ever needing to be declared.
+func xmlAttr(tag xmlreader.Tag, name string) (string, bool) {
+	for _, attr := range tag.Attrs {
+		if attr.SchemaName == "xml" && attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// isReservedAttribute reports whether attr belongs to RDF/XML's own
+// grammar (rdf:*, xmlns*, xml:*) rather than being a property attribute.
+func (parser *Parser) isReservedAttribute(scope *NamespaceScope, attr xmlreader.Attribute) bool {
+	if attr.SchemaName == "xml" {
+		return true
+	}
+	if _, isNamespaceDecl := namespacePrefix(attr); isNamespaceDecl {
+		return true
+	}
+	base, ok := scope.resolve(attr.SchemaName)
+	return ok && base == parser.rdfNS
+}
+
+// resolveIRI resolves ref against the xml:base in effect for scope, the
+// same way a browser resolves a relative href against its document's base.
+// If no ancestor declared an xml:base, or ref/base don't parse as URIs,
+// ref is returned unchanged.
+func (parser *Parser) resolveIRI(scope *NamespaceScope, ref string) string {
+	base := scope.effectiveBase()
+	if base == "" {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// processNodeElement turns a node element (a subject's own tag, or the
+// element implied by a nested property) into its rdf:type triple, its
+// property-attribute triples, and the triples for each of its property
+// element children. Any objects among those children that are themselves
+// node elements needing further processing are returned as pending, so
+// callers can choose how to walk them (concurrently, or inline).
+func (parser *Parser) processNodeElement(scope *NamespaceScope, block *xmlreader.Block, node *Node, appendTriple func(*Triple)) (pending []pendingChild, err error) {
+	typeURI, err := parser.uriFromPair(scope, block.OpeningTag.SchemaName, block.OpeningTag.Name)
+	if err != nil {
+		return nil, err
+	}
+	appendTriple(&Triple{
+		Subject:   node,
+		Predicate: &Node{NodeType: IRI, ID: parser.rdfNS.AddFragment("type").String()},
+		Object:    &Node{NodeType: IRI, ID: typeURI.String()},
+	})
+
+	return parser.processPropertyList(scope, block, node, appendTriple)
+}
+
+// processPropertyList turns a node element's property attributes and
+// property-element children into triples, without asserting an rdf:type
+// from the element's own tag name. processNodeElement calls this after
+// emitting the node's rdf:type; it is also used directly for the blank
+// node implied by rdf:parseType="Resource", whose type must not be derived
+// from the *property* element's tag name (parseTypeResourcePropertyElt).
+func (parser *Parser) processPropertyList(scope *NamespaceScope, block *xmlreader.Block, node *Node, appendTriple func(*Triple)) (pending []pendingChild, err error) {
+	for _, attr := range block.OpeningTag.Attrs {
+		if parser.isReservedAttribute(scope, attr) {
+			continue
+		}
+		attrURI, err := parser.uriFromPair(scope, attr.SchemaName, attr.Name)
+		if err != nil {
+			return nil, err
+		}
+		literal := &Node{NodeType: LITERAL, ID: attr.Value}
+		if lang, ok := scope.effectiveLang(); ok {
+			literal.Lang = lang
+		}
+		appendTriple(&Triple{Subject: node, Predicate: &Node{NodeType: IRI, ID: attrURI.String()}, Object: literal})
+	}
+
+	liCounter := 0
+	for _, predicateBlock := range block.Children {
+		childPending, err := parser.triplesForProperty(scope, node, predicateBlock, &liCounter, appendTriple)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, childPending...)
+	}
+	return pending, nil
+}
+
+// triplesForProperty turns one property element (predicateBlock) of
+// subject into the triple(s) it describes, following the RDF/XML
+// abbreviated syntax: rdf:parseType of Resource/Collection/Literal,
+// rdf:datatype, xml:lang, rdf:li container renumbering, and the plain
+// resource/literal/nested-node cases.
+func (parser *Parser) triplesForProperty(scope *NamespaceScope, subject *Node, predicateBlock *xmlreader.Block, liCounter *int, appendTriple func(*Triple)) ([]pendingChild, error) {
+	predicateTag := predicateBlock.OpeningTag
+	propertyURI, err := parser.predicateURI(scope, predicateTag, liCounter)
+	if err != nil {
+		return nil, err
+	}
+	predicate := &Node{NodeType: IRI, ID: propertyURI}
+
+	propScope, err := childScope(scope, predicateTag)
+	if err != nil {
+		return nil, err
+	}
+
+	if parseType, ok, err := parser.rdfAttr(propScope, predicateTag, "parseType"); err != nil {
+		return nil, err
+	} else if ok {
+		switch parseType {
+		case parseTypeResource:
+			blankNode := parser.blankNodeGetter.Get()
+			appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: &blankNode})
+			return []pendingChild{{scope: propScope, block: predicateBlock, node: &blankNode, propertyListOnly: true}}, nil
+
+		case parseTypeCollection:
+			head, pending, err := parser.collectionTriples(propScope, predicateBlock.Children, appendTriple)
+			if err != nil {
+				return nil, err
+			}
+			appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: head})
+			return pending, nil
+
+		case parseTypeLiteral:
+			// rdf:parseType="Literal"'s object is the element's XML content
+			// itself (an XML Literal), not its text with child markup
+			// stripped, so this uses InnerXML rather than Value.
+			appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: &Node{NodeType: LITERAL, ID: predicateBlock.InnerXML}})
+			return nil, nil
+		}
+	}
+
+	if resIdx, err := parser.getRDFAttributeIndex(propScope, predicateTag, "resource"); err != nil {
+		return nil, err
+	} else if resIdx != -1 {
+		resourceURI := parser.resolveIRI(propScope, predicateTag.Attrs[resIdx].Value)
+		appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: &Node{NodeType: IRI, ID: resourceURI}})
+		return nil, nil
+	}
+
+	if len(predicateBlock.Children) > 0 {
+		var pending []pendingChild
+		for _, objectBlock := range predicateBlock.Children {
+			objectScope, err := childScope(propScope, objectBlock.OpeningTag)
+			if err != nil {
+				return nil, err
+			}
+			objectNode, err := parser.nodeFromTag(objectScope, objectBlock.OpeningTag)
+			if err != nil {
+				return nil, err
+			}
+			appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: objectNode})
+			pending = append(pending, pendingChild{scope: objectScope, block: objectBlock, node: objectNode})
+		}
+		return pending, nil
+	}
+
+	literal := &Node{NodeType: LITERAL, ID: predicateBlock.Value}
+	if datatype, ok, err := parser.rdfAttr(propScope, predicateTag, "datatype"); err != nil {
+		return nil, err
+	} else if ok {
+		literal.Datatype = parser.resolveIRI(propScope, datatype)
+	} else if lang, ok := propScope.effectiveLang(); ok {
+		literal.Lang = lang
+	}
+	appendTriple(&Triple{Subject: subject, Predicate: predicate, Object: literal})
+	return nil, nil
+}
+
+// predicateURI resolves a property element's tag to its predicate IRI,
+// renumbering rdf:li to rdf:_1, rdf:_2, ... as required for rdf:Bag/Seq/Alt
+// containers.
+func (parser *Parser) predicateURI(scope *NamespaceScope, tag xmlreader.Tag, liCounter *int) (string, error) {
+	if tag.Name == "li" {
+		if base, ok := scope.resolve(tag.SchemaName); ok && base == parser.rdfNS {
+			*liCounter++
+			return parser.rdfNS.AddFragment(fmt.Sprintf("_%d", *liCounter)).String(), nil
+		}
+	}
+	propertyURI, err := parser.uriFromPair(scope, tag.SchemaName, tag.Name)
+	if err != nil {
+		return "", err
+	}
+	return propertyURI.String(), nil
+}
+
+// collectionTriples builds the rdf:first/rdf:rest/rdf:nil linked list for
+// an rdf:parseType="Collection" property, returning the list's head node
+// and the member node elements that still need their own properties
+// walked.
+func (parser *Parser) collectionTriples(scope *NamespaceScope, items []*xmlreader.Block, appendTriple func(*Triple)) (*Node, []pendingChild, error) {
+	nilNode := &Node{NodeType: IRI, ID: parser.rdfNS.AddFragment("nil").String()}
+	if len(items) == 0 {
+		return nilNode, nil, nil
+	}
+
+	firstURI := parser.rdfNS.AddFragment("first").String()
+	restURI := parser.rdfNS.AddFragment("rest").String()
+
+	var pending []pendingChild
+	var head, prev *Node
+	for _, itemBlock := range items {
+		itemScope, err := childScope(scope, itemBlock.OpeningTag)
+		if err != nil {
+			return nil, nil, err
+		}
+		itemNode, err := parser.nodeFromTag(itemScope, itemBlock.OpeningTag)
+		if err != nil {
+			return nil, nil, err
+		}
+		pending = append(pending, pendingChild{scope: itemScope, block: itemBlock, node: itemNode})
+
+		listNode := parser.blankNodeGetter.Get()
+		if head == nil {
+			head = &listNode
+		} else {
+			appendTriple(&Triple{Subject: prev, Predicate: &Node{NodeType: IRI, ID: restURI}, Object: &listNode})
+		}
+		appendTriple(&Triple{Subject: &listNode, Predicate: &Node{NodeType: IRI, ID: firstURI}, Object: itemNode})
+		prev = &listNode
+	}
+	appendTriple(&Triple{Subject: prev, Predicate: &Node{NodeType: IRI, ID: restURI}, Object: nilNode})
+	return head, pending, nil
+}
+
+// rdfAttr looks up an rdf:name attribute on tag, resolving its prefix
+// through scope so any prefix bound to the RDF namespace is recognised,
+// not just the conventional "rdf".
+func (parser *Parser) rdfAttr(scope *NamespaceScope, tag xmlreader.Tag, name string) (value string, ok bool, err error) {
+	index, err := parser.getRDFAttributeIndex(scope, tag, name)
+	if err != nil || index == -1 {
+		return "", false, err
+	}
+	return tag.Attrs[index].Value, true, nil
+}
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+// parseFixture writes doc to a temp file and parses it with Parse.
+func parseFixture(t *testing.T, doc string) *Parser {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "fixture-*.rdf")
+	if err != nil {
+		t.Fatalf("creating fixture: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(doc); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	p := New()
+	if err := p.Parse(context.Background(), f.Name()); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return p
+}
+
+// TestParseTypeResource_DoesNotAssertTypeFromPropertyName checks that the
+// blank node implied by rdf:parseType="Resource" gets its property list but
+// no rdf:type derived from the property element's own tag name.
+func TestParseTypeResource_DoesNotAssertTypeFromPropertyName(t *testing.T) {
+	p := parseFixture(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a">
+  <ex:address rdf:parseType="Resource">
+    <ex:city>Springfield</ex:city>
+  </ex:address>
+</rdf:Description>
+</rdf:RDF>`)
+
+	for _, triple := range p.Triples {
+		if triple.Predicate.ID == RDFNS+"type" && triple.Object.ID == "http://example.org/ns#address" {
+			t.Fatal("rdf:parseType=\"Resource\" must not assert rdf:type from the property element's tag name")
+		}
+	}
+
+	var sawCity bool
+	for _, triple := range p.Triples {
+		if triple.Predicate.ID == "http://example.org/ns#city" {
+			sawCity = true
+			if triple.Object.ID != "Springfield" {
+				t.Fatalf("got city %q, want Springfield", triple.Object.ID)
+			}
+			if triple.Subject.NodeType != BLANK {
+				t.Fatalf("the implied resource node should be a blank node, got %v", triple.Subject.NodeType)
+			}
+		}
+	}
+	if !sawCity {
+		t.Fatal("expected a triple for ex:city nested under the parseType=\"Resource\" property")
+	}
+}
+
+// TestParseTypeCollection_BuildsLinkedListWithoutDuplicates checks that
+// rdf:parseType="Collection" produces one rdf:first/rdf:rest pair per member
+// terminated by rdf:nil, with no member appearing twice (regression for the
+// top-level member duplication fixed earlier in this backlog).
+func TestParseTypeCollection_BuildsLinkedListWithoutDuplicates(t *testing.T) {
+	p := parseFixture(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a">
+  <ex:items rdf:parseType="Collection">
+    <ex:Item rdf:about="http://example.org/i1"/>
+    <ex:Item rdf:about="http://example.org/i2"/>
+  </ex:items>
+</rdf:Description>
+</rdf:RDF>`)
+
+	firstURI := RDFNS + "first"
+	firstCount := 0
+	seenObjects := map[string]int{}
+	for _, triple := range p.Triples {
+		if triple.Predicate.ID == firstURI {
+			firstCount++
+			seenObjects[triple.Object.ID]++
+		}
+	}
+	if firstCount != 2 {
+		t.Fatalf("got %d rdf:first triples, want 2", firstCount)
+	}
+	for id, n := range seenObjects {
+		if n != 1 {
+			t.Fatalf("collection member %q appeared %d times, want 1", id, n)
+		}
+	}
+	if seenObjects["http://example.org/i1"] != 1 || seenObjects["http://example.org/i2"] != 1 {
+		t.Fatalf("got members %v, want exactly i1 and i2 once each", seenObjects)
+	}
+}
+
+// TestParseTypeLiteral_ObjectIsReserializedXML checks that
+// rdf:parseType="Literal"'s object is the property element's inner XML
+// markup, not its text with child elements stripped.
+func TestParseTypeLiteral_ObjectIsReserializedXML(t *testing.T) {
+	p := parseFixture(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a">
+  <ex:bio rdf:parseType="Literal"><span>bold</span>text</ex:bio>
+</rdf:Description>
+</rdf:RDF>`)
+
+	want := "<span>bold</span>text"
+	var got string
+	var found bool
+	for _, triple := range p.Triples {
+		if triple.Predicate.ID == "http://example.org/ns#bio" {
+			found = true
+			got = triple.Object.ID
+		}
+	}
+	if !found {
+		t.Fatal("expected a triple for ex:bio")
+	}
+	if got != want {
+		t.Fatalf("got literal %q, want %q", got, want)
+	}
+}
+
+// TestParseAndParseStream_AgreeOnParseTypeLiteral is the regression test for
+// the bug where ParseStream's own block assembly never populated InnerXML:
+// parsing the same document with Parse and with ParseStream must produce an
+// identical rdf:parseType="Literal" object, not a silently empty one.
+func TestParseAndParseStream_AgreeOnParseTypeLiteral(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a">
+  <ex:bio rdf:parseType="Literal"><span>bold</span>text</ex:bio>
+</rdf:Description>
+</rdf:RDF>`
+
+	fileParser := parseFixture(t, doc)
+	var fileLiteral string
+	for _, triple := range fileParser.Triples {
+		if triple.Predicate.ID == "http://example.org/ns#bio" {
+			fileLiteral = triple.Object.ID
+		}
+	}
+
+	var streamed []*Triple
+	streamParser := New()
+	err := streamParser.ParseStream(context.Background(), bytes.NewReader([]byte(doc)), func(triple *Triple) error {
+		streamed = append(streamed, triple)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+
+	var streamLiteral string
+	for _, triple := range streamed {
+		if triple.Predicate.ID == "http://example.org/ns#bio" {
+			streamLiteral = triple.Object.ID
+		}
+	}
+
+	if fileLiteral == "" {
+		t.Fatal("Parse produced an empty rdf:parseType=\"Literal\" object; fixture is broken")
+	}
+	if streamLiteral != fileLiteral {
+		t.Fatalf("ParseStream literal %q does not match Parse literal %q", streamLiteral, fileLiteral)
+	}
+}
+
+// TestXMLLang_InheritsAcrossPropertyElements checks that xml:lang declared
+// on an ancestor element is inherited by a plain-literal property that
+// declares no xml:lang of its own.
+func TestXMLLang_InheritsAcrossPropertyElements(t *testing.T) {
+	p := parseFixture(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a" xml:lang="en">
+  <ex:title>Hello</ex:title>
+</rdf:Description>
+</rdf:RDF>`)
+
+	var found bool
+	for _, triple := range p.Triples {
+		if triple.Predicate.ID == "http://example.org/ns#title" {
+			found = true
+			if triple.Object.Lang != "en" {
+				t.Fatalf("got lang %q, want en", triple.Object.Lang)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a triple for ex:title")
+	}
+}
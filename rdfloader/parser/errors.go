@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// multiError accumulates errors reported by concurrent goroutines into a
+// single error value. Without it, goroutines racing to assign a shared
+// error variable drop all but one failure; multiError keeps every one of
+// them and surfaces them together to the caller of Parse/ParseStream.
+type multiError struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func newMultiError() *multiError {
+	return &multiError{}
+}
+
+// add records err, ignoring nil so callers can pass through the result of
+// a fallible call without checking it themselves.
+func (m *multiError) add(err error) {
+	if err == nil {
+		return
+	}
+	m.mu.Lock()
+	m.errs = append(m.errs, err)
+	m.mu.Unlock()
+}
+
+// errOrNil returns nil if nothing was recorded, the lone error if exactly
+// one was, or a combined error listing all of them otherwise.
+func (m *multiError) errOrNil() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch len(m.errs) {
+	case 0:
+		return nil
+	case 1:
+		return m.errs[0]
+	}
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%d errors occurred:\n\t* %s", len(m.errs), strings.Join(msgs, "\n\t* "))
+}
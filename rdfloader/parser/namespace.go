@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+
+	xmlreader "github.com/RishabhBhatnagar/gordf/rdfloader/xmlreader"
+	"github.com/RishabhBhatnagar/gordf/uri"
+)
+
+// NamespaceScope is one level of xmlns bindings, chained to the scope of
+// its enclosing element. RDF/XML documents are free to declare namespaces
+// on any element, not just the root, so a single flat map isn't enough:
+// resolving a prefix means walking up the chain to the nearest element
+// that bound it.
+type NamespaceScope struct {
+	parent   *NamespaceScope
+	bindings map[string]uri.URIRef
+	base     string // this element's own xml:base, if it declared one
+	lang     string // this element's own xml:lang, if it declared one
+	hasLang  bool   // distinguishes xml:lang="" (resets inheritance) from not declaring it
+}
+
+// resolve looks up prefix in scope, falling back to enclosing scopes. The
+// default namespace, declared with a bare xmlns="...", is stored under the
+// empty prefix.
+func (scope *NamespaceScope) resolve(prefix string) (uri.URIRef, bool) {
+	if scope == nil {
+		return uri.URIRef{}, false
+	}
+	if uriref, ok := scope.bindings[prefix]; ok {
+		return uriref, true
+	}
+	return scope.parent.resolve(prefix)
+}
+
+// effectiveBase returns the nearest xml:base in scope's chain, or "" if
+// none of its ancestors declared one.
+func (scope *NamespaceScope) effectiveBase() string {
+	if scope == nil {
+		return ""
+	}
+	if scope.base != "" {
+		return scope.base
+	}
+	return scope.parent.effectiveBase()
+}
+
+// effectiveLang returns the nearest xml:lang in scope's chain and whether
+// any ancestor declared one; xml:lang="" is a valid declaration that resets
+// inheritance to "no language", so the bool distinguishes that from no
+// ancestor having declared xml:lang at all.
+func (scope *NamespaceScope) effectiveLang() (string, bool) {
+	if scope == nil {
+		return "", false
+	}
+	if scope.hasLang {
+		return scope.lang, true
+	}
+	return scope.parent.effectiveLang()
+}
+
+// childScope builds the namespace scope in effect for tag, given the scope
+// of its enclosing element. It only allocates a new frame when tag
+// actually declares xmlns bindings of its own; otherwise the parent scope
+// is reused unchanged.
+func childScope(parent *NamespaceScope, tag xmlreader.Tag) (*NamespaceScope, error) {
+	var bindings map[string]uri.URIRef
+
+	for _, attr := range tag.Attrs {
+		prefix, isNamespaceDecl := namespacePrefix(attr)
+		if !isNamespaceDecl {
+			continue
+		}
+		if bindings == nil {
+			bindings = map[string]uri.URIRef{}
+		}
+		if _, redefined := bindings[prefix]; redefined {
+			return nil, fmt.Errorf("namespace prefix %q is declared more than once on the same tag", prefix)
+		}
+		uriref, err := uri.NewURIRef(attr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("schema URI %v doesn't confirm to URL rules", attr.Value)
+		}
+		bindings[prefix] = uriref
+	}
+
+	base, hasBase := xmlAttr(tag, "base")
+	lang, hasLang := xmlAttr(tag, "lang")
+
+	if bindings == nil && !hasBase && !hasLang {
+		// tag declared no namespaces, base, or lang of its own: reuse the
+		// parent scope rather than growing the chain for nothing.
+		return parent, nil
+	}
+	return &NamespaceScope{parent: parent, bindings: bindings, base: base, lang: lang, hasLang: hasLang}, nil
+}
+
+// namespacePrefix reports whether attr is a namespace declaration
+// (xmlns:prefix="uri" or the default xmlns="uri") and, if so, the prefix
+// it binds ("" for the default namespace).
+func namespacePrefix(attr xmlreader.Attribute) (prefix string, isNamespaceDecl bool) {
+	if attr.SchemaName == "xmlns" {
+		return attr.Name, true
+	}
+	if attr.SchemaName == "" && attr.Name == "xmlns" {
+		return "", true
+	}
+	return "", false
+}
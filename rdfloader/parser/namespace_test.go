@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"testing"
+
+	xmlreader "github.com/RishabhBhatnagar/gordf/rdfloader/xmlreader"
+)
+
+func nsAttr(prefix, uri string) xmlreader.Attribute {
+	return xmlreader.Attribute{SchemaName: "xmlns", Name: prefix, Value: uri}
+}
+
+func TestChildScope_ReusesParentWhenTagDeclaresNothing(t *testing.T) {
+	root, err := childScope(nil, xmlreader.Tag{Attrs: []xmlreader.Attribute{nsAttr("dc", "http://purl.org/dc/elements/1.1/")}})
+	if err != nil {
+		t.Fatalf("childScope(root): %v", err)
+	}
+
+	child, err := childScope(root, xmlreader.Tag{Name: "Description"})
+	if err != nil {
+		t.Fatalf("childScope(child): %v", err)
+	}
+	if child != root {
+		t.Fatal("childScope should reuse the parent scope when the tag declares no xmlns/base/lang of its own")
+	}
+}
+
+func TestChildScope_PrefixIsScopedToItsSubtree(t *testing.T) {
+	root, err := childScope(nil, xmlreader.Tag{})
+	if err != nil {
+		t.Fatalf("childScope(root): %v", err)
+	}
+
+	withDC, err := childScope(root, xmlreader.Tag{Attrs: []xmlreader.Attribute{nsAttr("dc", "http://purl.org/dc/elements/1.1/")}})
+	if err != nil {
+		t.Fatalf("childScope(withDC): %v", err)
+	}
+
+	if _, ok := root.resolve("dc"); ok {
+		t.Fatal("a prefix declared on a child element must not be visible from its parent's scope")
+	}
+	if ns, ok := withDC.resolve("dc"); !ok || ns.String() != "http://purl.org/dc/elements/1.1/" {
+		t.Fatalf("got (%v, %v), want (http://purl.org/dc/elements/1.1/, true)", ns, ok)
+	}
+
+	grandchild, err := childScope(withDC, xmlreader.Tag{})
+	if err != nil {
+		t.Fatalf("childScope(grandchild): %v", err)
+	}
+	if ns, ok := grandchild.resolve("dc"); !ok || ns.String() != "http://purl.org/dc/elements/1.1/" {
+		t.Fatal("a descendant scope should still resolve a prefix bound by an ancestor")
+	}
+}
+
+func TestChildScope_RejectsDuplicatePrefixOnSameTag(t *testing.T) {
+	tag := xmlreader.Tag{Attrs: []xmlreader.Attribute{nsAttr("dc", "http://a/"), nsAttr("dc", "http://b/")}}
+	if _, err := childScope(nil, tag); err == nil {
+		t.Fatal("expected an error for a prefix declared twice on the same tag")
+	}
+}
+
+func TestEffectiveBase_InheritsFromNearestAncestor(t *testing.T) {
+	root, err := childScope(nil, xmlreader.Tag{Attrs: []xmlreader.Attribute{{SchemaName: "xml", Name: "base", Value: "http://example.org/"}}})
+	if err != nil {
+		t.Fatalf("childScope(root): %v", err)
+	}
+	child, err := childScope(root, xmlreader.Tag{})
+	if err != nil {
+		t.Fatalf("childScope(child): %v", err)
+	}
+	if got := child.effectiveBase(); got != "http://example.org/" {
+		t.Fatalf("got effectiveBase() = %q, want %q", got, "http://example.org/")
+	}
+
+	grandchild, err := childScope(child, xmlreader.Tag{Attrs: []xmlreader.Attribute{{SchemaName: "xml", Name: "base", Value: "http://other.org/"}}})
+	if err != nil {
+		t.Fatalf("childScope(grandchild): %v", err)
+	}
+	if got := grandchild.effectiveBase(); got != "http://other.org/" {
+		t.Fatalf("a nested xml:base should override the ancestor's: got %q, want %q", got, "http://other.org/")
+	}
+}
+
+func TestEffectiveLang_InheritsAndResetsOnEmptyDeclaration(t *testing.T) {
+	root, err := childScope(nil, xmlreader.Tag{Attrs: []xmlreader.Attribute{{SchemaName: "xml", Name: "lang", Value: "en"}}})
+	if err != nil {
+		t.Fatalf("childScope(root): %v", err)
+	}
+	if lang, ok := root.effectiveLang(); !ok || lang != "en" {
+		t.Fatalf("got (%q, %v), want (en, true)", lang, ok)
+	}
+
+	child, err := childScope(root, xmlreader.Tag{})
+	if err != nil {
+		t.Fatalf("childScope(child): %v", err)
+	}
+	if lang, ok := child.effectiveLang(); !ok || lang != "en" {
+		t.Fatalf("xml:lang should inherit to a child with no xml:lang of its own: got (%q, %v)", lang, ok)
+	}
+
+	reset, err := childScope(root, xmlreader.Tag{Attrs: []xmlreader.Attribute{{SchemaName: "xml", Name: "lang", Value: ""}}})
+	if err != nil {
+		t.Fatalf("childScope(reset): %v", err)
+	}
+	if lang, ok := reset.effectiveLang(); !ok || lang != "" {
+		t.Fatalf("xml:lang=\"\" should reset inheritance to no language: got (%q, %v), want (\"\", true)", lang, ok)
+	}
+
+	noLang, err := childScope(nil, xmlreader.Tag{})
+	if err != nil {
+		t.Fatalf("childScope(noLang): %v", err)
+	}
+	if _, ok := noLang.effectiveLang(); ok {
+		t.Fatal("effectiveLang should report false when no ancestor ever declared xml:lang")
+	}
+}
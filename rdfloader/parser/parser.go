@@ -1,21 +1,35 @@
 package parser
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
+
 	xmlreader "github.com/RishabhBhatnagar/gordf/rdfloader/xmlreader"
 	"github.com/RishabhBhatnagar/gordf/uri"
-	"sync"
 )
 
 const RDFNS = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
 
 type Parser struct {
-	Triples          map[string]*Triple
-	writeLock        sync.RWMutex
-	schemaDefinition map[string]uri.URIRef
-	blankNodeGetter  BlankNodeGetter
-	rdfNS            uri.URIRef
-	wg               sync.WaitGroup
+	Triples         map[string]*Triple
+	writeLock       sync.Mutex
+	blankNodeGetter BlankNodeGetter
+	rdfNS           uri.URIRef
+
+	// MaxWorkers bounds how many blocks parseBlock will walk concurrently.
+	// Zero (the default from New()) means runtime.NumCPU().
+	MaxWorkers int
+}
+
+// maxWorkers is the effective concurrency bound for a parse: MaxWorkers if
+// the caller set one, otherwise one worker per CPU.
+func (parser *Parser) maxWorkers() int {
+	if parser.MaxWorkers > 0 {
+		return parser.MaxWorkers
+	}
+	return runtime.NumCPU()
 }
 
 type Triple struct {
@@ -26,31 +40,13 @@ func (triple *Triple) Hash() string {
 	return fmt.Sprintf("{%v; %v; %v}", triple.Subject, triple.Predicate, triple.Object)
 }
 
-func parseHeaderBlock(rootBlock xmlreader.Block) (map[string]uri.URIRef, error) {
-	// returns all the schema definitions in the root block.
-	// a schema definition is of the form xmlns:SchemaName="URI",
-
-	namespaceURI := map[string]uri.URIRef{}
-
-	for _, attr := range rootBlock.OpeningTag.Attrs {
-		if attr.SchemaName == "xmlns" {
-			uriref, err := uri.NewURIRef(attr.Value)
-			if err != nil {
-				return namespaceURI, fmt.Errorf("schema URI %v doesn't confirm to URL rules", rootBlock)
-			}
-			namespaceURI[attr.Name] = uriref
-		}
-	}
-	return namespaceURI, nil
-}
-
-func (parser *Parser) uriFromPair(schemaName, name string) (mergedUri uri.URIRef, err error) {
+func (parser *Parser) uriFromPair(scope *NamespaceScope, schemaName, name string) (mergedUri uri.URIRef, err error) {
 	// returns the uri representation of a pair of strings.
 	// name:schemaName is an example of pair.
-	// pairs such as rdf:RDF, where, rdf must be a valid xmlns schema name.
+	// pairs such as rdf:RDF, where, rdf must be a valid xmlns schema name
+	// bound in scope or one of its ancestors.
 
-	// base must be a valid schema name defined in the root tag.
-	baseURI, ok := parser.schemaDefinition[schemaName]
+	baseURI, ok := scope.resolve(schemaName)
 	if !ok {
 		return uri.URIRef{}, fmt.Errorf("undefined schema name: %v", schemaName)
 	}
@@ -59,21 +55,32 @@ func (parser *Parser) uriFromPair(schemaName, name string) (mergedUri uri.URIRef
 	return baseURI.AddFragment(name), nil
 }
 
+// appendTriple is the only path that writes to Triples, so a single Mutex
+// guards every write made by the worker pool; there is no concurrent
+// reader to justify the extra bookkeeping of an RWMutex.
 func (parser *Parser) appendTriple(triple *Triple) {
 	parser.writeLock.Lock()
 	parser.Triples[triple.Hash()] = triple
 	parser.writeLock.Unlock()
 }
 
-func (parser *Parser) getRDFAttributeIndex(tag xmlreader.Tag, attrName string) (index int, err error) {
+func (parser *Parser) getRDFAttributeIndex(scope *NamespaceScope, tag xmlreader.Tag, attrName string) (index int, err error) {
 	/*
 		From all the attribute of the given tag, return the index of the attribute rdf:attrName
 	*/
 	index = -1
 	for i, attr := range tag.Attrs {
-		attrUri, err := parser.uriFromPair(attr.SchemaName, attr.Name)
+		if attr.SchemaName == "xml" || attr.SchemaName == "xmlns" || (attr.SchemaName == "" && attr.Name == "xmlns") {
+			// xml: attributes (xml:lang, xml:base, ...) and namespace
+			// declarations are never rdf:attrName - and xml's prefix in
+			// particular is never bound by an xmlns declaration, so
+			// resolving it through scope would always fail.
+			continue
+		}
+		var attrUri uri.URIRef
+		attrUri, err = parser.uriFromPair(scope, attr.SchemaName, attr.Name)
 		if err != nil {
-			break
+			return -1, err
 		}
 		if attrUri == parser.rdfNS.AddFragment(attrName) {
 			// current attribute is a rdf:attrName tag,
@@ -84,41 +91,46 @@ func (parser *Parser) getRDFAttributeIndex(tag xmlreader.Tag, attrName string) (
 	return
 }
 
-func (parser *Parser) nodeFromTag(openingTag xmlreader.Tag) (node *Node, err error) {
+func (parser *Parser) nodeFromTag(scope *NamespaceScope, openingTag xmlreader.Tag) (node *Node, err error) {
 	// returns the node object from the opening tag of any block.
 	// https://www.w3.org/TR/rdf-syntax-grammar/figure1.png has sample image having 5 nodes.
 	// 		one of them is a blank node.
 
 	// description of the entire function:
-	// if the opening tag has an attribute of rdf:about,
-	//		the node will represented by the value of rdf:about attribute
-	// else, it is a blank node.
+	// if the opening tag has an attribute of rdf:about, the node is
+	//		represented by the (base-resolved) value of rdf:about;
+	// else if it has an rdf:ID, the node is a base-relative fragment;
+	// else if it has an rdf:nodeID, the node is that named blank node;
+	// else it is a fresh blank node.
+
+	index, err := parser.getRDFAttributeIndex(scope, openingTag, "about")
+	if err != nil {
+		return
+	}
+	if index != -1 {
+		currentNode := Node{NodeType: IRI, ID: parser.resolveIRI(scope, openingTag.Attrs[index].Value)}
+		return &currentNode, nil
+	}
 
-	// checking if any of the attributes is a rdf:about attribute
-	index, err := parser.getRDFAttributeIndex(openingTag, "about")
+	idIndex, err := parser.getRDFAttributeIndex(scope, openingTag, "ID")
 	if err != nil {
 		return
 	}
+	if idIndex != -1 {
+		currentNode := Node{NodeType: IRI, ID: parser.resolveIRI(scope, "#"+openingTag.Attrs[idIndex].Value)}
+		return &currentNode, nil
+	}
+
+	rdfNodeIDIndex, err := parser.getRDFAttributeIndex(scope, openingTag, "nodeID")
+	if err != nil {
+		return nil, err
+	}
 
 	var currentNode Node
-	if index == -1 {
-		// we didnt' find rdf:about in the attributes of the opening tag.
-		// returning a new blank node.
-		rdfNodeIDIndex, err := parser.getRDFAttributeIndex(openingTag, "nodeID")
-		if err != nil {
-			return nil, err
-		}
-		if rdfNodeIDIndex == -1 {
-			currentNode = parser.blankNodeGetter.Get()
-		} else {
-			currentNode = parser.blankNodeGetter.GetFromId(openingTag.Attrs[rdfNodeIDIndex].Value)
-		}
+	if rdfNodeIDIndex == -1 {
+		currentNode = parser.blankNodeGetter.Get()
 	} else {
-		// we found a rdf:about tag.
-		currentNode = Node{
-			NodeType: IRI,
-			ID:       openingTag.Attrs[index].Value,
-		}
+		currentNode = parser.blankNodeGetter.GetFromId(openingTag.Attrs[rdfNodeIDIndex].Value)
 	}
 	return &currentNode, nil
 }
@@ -127,142 +139,112 @@ func New() (parser *Parser) {
 	// creates a new parser object
 	rdfNS, _ := uri.NewURIRef(RDFNS)
 	return &Parser{
-		Triples:          map[string]*Triple{},
-		writeLock:        sync.RWMutex{},
-		schemaDefinition: map[string]uri.URIRef{"": uri.URIRef{}},
-		blankNodeGetter:  BlankNodeGetter{-1},
-		wg:               sync.WaitGroup{},
-		rdfNS:            rdfNS,
+		Triples:         map[string]*Triple{},
+		blankNodeGetter: BlankNodeGetter{counter: -1},
+		rdfNS:           rdfNS,
 	}
 }
 
-func (parser *Parser) parseBlock(currBlock *xmlreader.Block, node *Node, errp *error) {
-	/*
-		1. What is a block?
-		Ans: A rdf block is made up of
-				1. Root Node (IRI Ref or BlankNode) :: Subject
-				2. Link (IRI Ref)                   :: Object
-				3. anotherBlock (Literal or IRI Ref or Blank Node) :: Predicate
-
-		2. Example of a Block.
-			Sample RDF/XML input with non-blank subject and literal predicate.
-				<spdx:License rdf:about="http://spdx.org/licenses/Apache-2.0">
-					<spdx:licenseId>Apache-2.0</spdx:licenseId>
-				</spdx:License>
-			Output Components:
-				Subject:   http://spdx.org/licenses/Apache-2.0  (IRI Ref)
-				Object:    spdx:licenseId						(IRI Ref)
-				Predicate: Apacha-2.0							(Literal)
-
-			If the rdf:about attribute of the subject is removed, it will become a blank node.
-
-		3. What is a node *Node?
-		Ans: effectively, node representation of the block parameter.
-			 node := parser.nodeFromTag(block)
-
-		4. Parameter errp.
-			Pointer to an error variable.
-			used to report errors in a concurrent environment.
-			why pointer? Because go func() cannot return anything.
-	*/
-	for _, predicateBlock := range currBlock.Children {
-		predicateNode, newErr := parser.nodeFromTag(predicateBlock.OpeningTag)
-		*errp = newErr
-		if *errp != nil {
-			return
-		}
-		openingTagUri, newErr := parser.uriFromPair(currBlock.OpeningTag.SchemaName, currBlock.OpeningTag.Name)
-		*errp = newErr
-		if *errp != nil {
-			return
-		}
-		predicateURI := parser.rdfNS.AddFragment("type")
-		parser.appendTriple(&Triple{
-			Subject:   node,
-			Predicate: &Node{IRI, predicateURI.String()},
-			Object:    &Node{IRI, openingTagUri.String()},
-		})
-		if *errp != nil {
-			return
-		}
-		if len(predicateBlock.Children) == 0 {
-			// no children.
-			var objectString string
-			resIdx, newErr := parser.getRDFAttributeIndex(predicateBlock.OpeningTag, "resource")
-			*errp = newErr
-			if *errp != nil {
-				return
-			}
-			if resIdx != -1 {
-				// rdf:resource attribute is present
-				objectString = predicateBlock.OpeningTag.Attrs[resIdx].Value
-			} else {
-				objectString = predicateBlock.Value
-			}
-
-			// registering a new Triple:
-			// (currentNode) -> rdf:type -> (openingTagURI)
-			parser.appendTriple(&Triple{
-				Subject:   node,
-				Predicate: predicateNode,
-				Object:    &Node{LITERAL, objectString},
-			})
-		}
+// parseBlock turns one node element into triples and recurses into its
+// pending children, all on the Parser's bounded worker pool:
+//
+//  1. What is a block?
+//     Ans: A rdf block is made up of
+//
+//  1. Root Node (IRI Ref or BlankNode) :: Subject
+//
+//  2. Link (IRI Ref)                   :: Object
+//
+//  3. anotherBlock (Literal or IRI Ref or Blank Node) :: Predicate
+//
+//  2. Example of a Block.
+//     Sample RDF/XML input with non-blank subject and literal predicate.
+//     <spdx:License rdf:about="http://spdx.org/licenses/Apache-2.0">
+//     <spdx:licenseId>Apache-2.0</spdx:licenseId>
+//     </spdx:License>
+//     Output Components:
+//     Subject:   http://spdx.org/licenses/Apache-2.0  (IRI Ref)
+//     Object:    spdx:licenseId                        (IRI Ref)
+//     Predicate: Apache-2.0                            (Literal)
+//     If the rdf:about attribute of the subject is removed, it will become a blank node.
+//
+// The actual triple-generation rules (rdf:parseType, containers, property
+// attributes, ...) live in processNodeElement/processPropertyList, shared
+// with ParseStream via processPendingChild. propertyListOnly is threaded
+// through for the blank node implied by rdf:parseType="Resource", which
+// must not assert an rdf:type derived from the property element's own tag.
+// parseBlock's own job is bookkeeping: claim a slot in sem before doing any
+// work, release it on the way out, record failures in errs instead of
+// racing to assign a shared error variable, and always call wg.Done() so
+// Parse's wg.Wait() is guaranteed to return even when a branch fails or
+// ctx is cancelled mid-walk.
+func (parser *Parser) parseBlock(ctx context.Context, sem chan struct{}, wg *sync.WaitGroup, errs *multiError, currBlock *xmlreader.Block, scope *NamespaceScope, node *Node, propertyListOnly bool) {
+	defer wg.Done()
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		errs.add(ctx.Err())
+		return
+	}
+	defer func() { <-sem }()
 
-		// the predicate block has children
-		for _, objectBlock := range predicateBlock.Children {
-			objectNode, newErr := parser.nodeFromTag(objectBlock.OpeningTag)
-			*errp = newErr
-			if *errp != nil {
-				return
-			}
+	if ctx.Err() != nil {
+		errs.add(ctx.Err())
+		return
+	}
 
-			parser.appendTriple(&Triple{
-				Subject:   node,
-				Predicate: predicateNode,
-				Object:    objectNode,
-			})
-			parser.wg.Add(1)
-			go parser.parseBlock(objectBlock, objectNode, errp)
-			if *errp != nil {
-				return
-			}
-		}
+	pending, err := parser.processPendingChild(pendingChild{scope: scope, block: currBlock, node: node, propertyListOnly: propertyListOnly}, parser.appendTriple)
+	if err != nil {
+		errs.add(err)
+		return
+	}
+	for _, child := range pending {
+		wg.Add(1)
+		go parser.parseBlock(ctx, sem, wg, errs, child.block, child.scope, child.node, child.propertyListOnly)
 	}
-	parser.wg.Done()
 }
 
-func (parser *Parser) Parse(filePath string) (err error) {
-	// reader for xml file
+// Parse reads the rdf/xml document at filePath into parser.Triples,
+// walking node elements concurrently across a pool of at most
+// parser.maxWorkers() goroutines. Every error encountered - whether from a
+// malformed element or from ctx being cancelled mid-parse - is collected
+// and returned together rather than dropped; Parse itself always waits for
+// every goroutine it started to finish before returning.
+func (parser *Parser) Parse(ctx context.Context, filePath string) error {
 	reader, err := xmlreader.XMLReaderFromFilePath(filePath)
 	if err != nil {
 		return err
 	}
-	// parsing the xml file
 	rootBlock, err := reader.Read()
 	if err != nil {
 		return err
 	}
 
-	// set all the schema definitions in the root block.
-	schemaDefinition, err := parseHeaderBlock(rootBlock)
+	// the root's own xmlns declarations are the outermost namespace scope.
+	rootScope, err := childScope(nil, rootBlock.OpeningTag)
 	if err != nil {
 		return err
 	}
-	parser.schemaDefinition = schemaDefinition
 
-	// root tag is set now.
+	sem := make(chan struct{}, parser.maxWorkers())
+	errs := newMultiError()
+	var wg sync.WaitGroup
+
 	for _, child := range rootBlock.Children {
-		parser.wg.Add(1)
-		childNode, err := parser.nodeFromTag(child.OpeningTag)
+		scope, err := childScope(rootScope, child.OpeningTag)
 		if err != nil {
-			return err
+			errs.add(err)
+			continue
 		}
-		go parser.parseBlock(child, childNode, &err)
+		childNode, err := parser.nodeFromTag(scope, child.OpeningTag)
 		if err != nil {
-			return err
+			errs.add(err)
+			continue
 		}
+		wg.Add(1)
+		go parser.parseBlock(ctx, sem, &wg, errs, child, scope, childNode, false)
 	}
-	parser.wg.Wait()
-	return nil
+	wg.Wait()
+	return errs.errOrNil()
 }
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// spdxFixture writes a synthetic SPDX-shaped RDF/XML document with n
+// license subjects to a temp file and returns its path, for benchmarking
+// Parse's worker-pool scaling against a range of document sizes.
+func spdxFixture(tb testing.TB, n int) string {
+	tb.Helper()
+
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0"?>` + "\n")
+	body.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:spdx="http://spdx.org/rdf/terms#">` + "\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&body, "<spdx:License rdf:about=\"http://spdx.org/licenses/License-%d\">"+
+			"<spdx:licenseId>License-%d</spdx:licenseId>"+
+			"<spdx:name>Sample License %d</spdx:name>"+
+			"</spdx:License>\n", i, i, i)
+	}
+	body.WriteString("</rdf:RDF>\n")
+
+	f, err := os.CreateTemp(tb.TempDir(), "spdx-*.rdf")
+	if err != nil {
+		tb.Fatalf("creating fixture: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(body.String()); err != nil {
+		tb.Fatalf("writing fixture: %v", err)
+	}
+	return f.Name()
+}
+
+// BenchmarkParse_SPDXScaling parses synthetic SPDX-shaped documents of
+// increasing subject count to demonstrate that Parse's bounded worker pool
+// scales roughly linearly with document size rather than degrading as the
+// number of goroutines it spawns grows.
+func BenchmarkParse_SPDXScaling(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		path := spdxFixture(b, n)
+		b.Run(fmt.Sprintf("subjects=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p := New()
+				if err := p.Parse(context.Background(), path); err != nil {
+					b.Fatalf("Parse: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestParse_TripleCountScalesWithSubjectCount is a correctness counterpart
+// to BenchmarkParse_SPDXScaling: each synthetic License subject carries
+// exactly one rdf:type, one spdx:licenseId, and one spdx:name triple, so the
+// total triple count must scale exactly linearly with subject count rather
+// than merely "finish without panicking" the way a benchmark alone would
+// show.
+func TestParse_TripleCountScalesWithSubjectCount(t *testing.T) {
+	for _, n := range []int{1, 10, 100} {
+		path := spdxFixture(t, n)
+		p := New()
+		if err := p.Parse(context.Background(), path); err != nil {
+			t.Fatalf("n=%d: Parse: %v", n, err)
+		}
+		if got, want := len(p.Triples), n*3; got != want {
+			t.Fatalf("n=%d: got %d triples, want %d", n, got, want)
+		}
+	}
+}
+
+// TestParse_RespectsContextCancellation checks that Parse stops and surfaces
+// ctx.Err() once its context is cancelled, rather than the worker pool
+// silently running to completion regardless of ctx.
+func TestParse_RespectsContextCancellation(t *testing.T) {
+	path := spdxFixture(t, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := New()
+	err := p.Parse(ctx, path)
+	if err == nil {
+		t.Fatal("expected an error from a pre-cancelled context")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want one wrapping context.Canceled", err)
+	}
+}
+
+// TestParse_MaxWorkersBoundsConcurrencyWithoutDroppingWork checks that
+// capping MaxWorkers to 1 still visits every subject - i.e. the worker pool
+// serializes rather than drops work when the semaphore is tight.
+func TestParse_MaxWorkersBoundsConcurrencyWithoutDroppingWork(t *testing.T) {
+	path := spdxFixture(t, 20)
+
+	p := New()
+	p.MaxWorkers = 1
+	if err := p.Parse(context.Background(), path); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, want := len(p.Triples), 20*3; got != want {
+		t.Fatalf("got %d triples, want %d", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	xmlreader "github.com/RishabhBhatnagar/gordf/rdfloader/xmlreader"
+)
+
+// ParseStream parses an rdf/xml document from r without materializing the
+// whole document tree in memory: at any point it only holds the subtree of
+// the subject currently being read, so documents with billions of triples
+// can be processed in bounded memory. Each triple is handed to emit as soon
+// as its surrounding subject block closes. ParseStream stops and returns
+// emit's error the first time it returns one, so callers can cancel a long
+// parse by returning a sentinel error from emit; it also stops and returns
+// ctx.Err() as soon as ctx is cancelled, checked once per event.
+func (parser *Parser) ParseStream(ctx context.Context, r io.Reader, emit func(*Triple) error) (err error) {
+	reader := xmlreader.XMLReaderFromFileObject(bufio.NewReader(r))
+
+	var rootScope *NamespaceScope
+	var blockStack []*xmlreader.Block // open blocks below the root, innermost last
+	var scopeStack []*NamespaceScope  // scope in effect for the matching entry in blockStack
+	// captureStack[i] is true once blockStack[i] or one of its ancestors is
+	// an rdf:parseType="Literal" property element - see buildBlock in
+	// xmlreader.go for why InnerXML is only accumulated in that case.
+	var captureStack []bool
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		event, err := reader.NextEvent()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case xmlreader.EventProlog, xmlreader.EventComment:
+			continue
+
+		case xmlreader.EventStartTag:
+			if rootScope == nil {
+				rootScope, err = childScope(nil, event.Tag)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			parentScope := rootScope
+			if len(scopeStack) > 0 {
+				parentScope = scopeStack[len(scopeStack)-1]
+			}
+			scope, err := childScope(parentScope, event.Tag)
+			if err != nil {
+				return err
+			}
+
+			captures := len(captureStack) > 0 && captureStack[len(captureStack)-1]
+			captures = captures || xmlreader.HasLiteralParseType(event.Tag)
+
+			block := &xmlreader.Block{OpeningTag: event.Tag}
+			if len(blockStack) > 0 {
+				parent := blockStack[len(blockStack)-1]
+				parent.Children = append(parent.Children, block)
+			}
+			blockStack = append(blockStack, block)
+			scopeStack = append(scopeStack, scope)
+			captureStack = append(captureStack, captures)
+			if event.SelfClosing {
+				if err := parser.popBlock(&blockStack, &scopeStack, &captureStack, emit); err != nil {
+					return err
+				}
+			}
+
+		case xmlreader.EventText:
+			if len(blockStack) > 0 {
+				blockStack[len(blockStack)-1].Value += event.Text
+				if captureStack[len(captureStack)-1] {
+					blockStack[len(blockStack)-1].InnerXML += xmlreader.EscapeXMLText(event.Text)
+				}
+			}
+
+		case xmlreader.EventEndTag:
+			if len(blockStack) == 0 {
+				continue // closing the root tag.
+			}
+			if err := parser.popBlock(&blockStack, &scopeStack, &captureStack, emit); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// popBlock closes the innermost open block. If doing so empties the stack,
+// the block that just closed was a direct child of the root - i.e. a
+// complete subject - so it is turned into triples and handed to emit
+// before being dropped from memory.
+func (parser *Parser) popBlock(blockStack *[]*xmlreader.Block, scopeStack *[]*NamespaceScope, captureStack *[]bool, emit func(*Triple) error) error {
+	blocks, scopes, captures := *blockStack, *scopeStack, *captureStack
+	block, scope := blocks[len(blocks)-1], scopes[len(scopes)-1]
+	*blockStack, *scopeStack, *captureStack = blocks[:len(blocks)-1], scopes[:len(scopes)-1], captures[:len(captures)-1]
+
+	if len(*blockStack) != 0 {
+		// captures[len(captures)-1], before truncation, was this block's
+		// own (already-inherited) flag - not useful here. What matters is
+		// whether the *parent*, now on top of the truncated stack, is
+		// itself accumulating InnerXML.
+		if (*captureStack)[len(*captureStack)-1] {
+			parent := (*blockStack)[len(*blockStack)-1]
+			parent.InnerXML += xmlreader.RenderElement(block)
+		}
+		return nil
+	}
+
+	node, err := parser.nodeFromTag(scope, block.OpeningTag)
+	if err != nil {
+		return err
+	}
+	return parser.emitBlock(pendingChild{scope: scope, block: block, node: node}, emit)
+}
+
+// emitBlock walks a fully read subject block through the same
+// processNodeElement/processPropertyList rules parseBlock uses (dispatched
+// via processPendingChild, so the blank node implied by
+// rdf:parseType="Resource" only gets its property list), but forwards
+// triples to emit instead of collecting them on the Parser, and walks
+// pending node elements inline rather than spawning goroutines: ParseStream
+// already processes one subject at a time, in document order.
+func (parser *Parser) emitBlock(child pendingChild, emit func(*Triple) error) error {
+	var emitErr error
+	appendTriple := func(triple *Triple) {
+		if emitErr != nil {
+			return
+		}
+		emitErr = emit(triple)
+	}
+
+	pending, err := parser.processPendingChild(child, appendTriple)
+	if err != nil {
+		return err
+	}
+	if emitErr != nil {
+		return emitErr
+	}
+
+	for _, grandchild := range pending {
+		if err := parser.emitBlock(grandchild, emit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
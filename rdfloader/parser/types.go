@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NodeType discriminates the three kinds of RDF term a Node can hold.
+type NodeType int
+
+const (
+	IRI NodeType = iota
+	BLANK
+	LITERAL
+)
+
+// Node is one RDF term: a subject, predicate or object of a Triple. Lang
+// and Datatype only apply to LITERAL nodes, and are mutually exclusive per
+// the RDF/XML grammar (xml:lang vs rdf:datatype).
+type Node struct {
+	NodeType NodeType
+	ID       string
+	Lang     string
+	Datatype string
+}
+
+func (node *Node) String() string {
+	return node.ID
+}
+
+// BlankNodeGetter hands out blank node identifiers, reusing the same Node
+// for every rdf:nodeID reference that names the same id within a parse.
+// Get is called concurrently by Parser's worker pool, so counter is guarded
+// by a mutex rather than incremented directly.
+type BlankNodeGetter struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// Get returns a fresh, never-before-seen blank node.
+func (getter *BlankNodeGetter) Get() Node {
+	getter.mu.Lock()
+	getter.counter++
+	id := getter.counter
+	getter.mu.Unlock()
+	return Node{NodeType: BLANK, ID: fmt.Sprintf("genid%v", id)}
+}
+
+// GetFromId returns the blank node identified by nodeID, as written via an
+// rdf:nodeID attribute.
+func (getter *BlankNodeGetter) GetFromId(nodeID string) Node {
+	return Node{NodeType: BLANK, ID: nodeID}
+}
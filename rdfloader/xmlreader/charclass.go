@@ -0,0 +1,84 @@
+package rdfloader
+
+// isNameStartChar reports whether r may begin an XML Name, per the
+// NameStartChar production in the XML 1.0/1.1 spec. Colons are handled
+// separately by readColonPair, so they are deliberately excluded here.
+func isNameStartChar(r rune) bool {
+	switch {
+	case r == '_':
+		return true
+	case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		return true
+	case r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x2FF:
+		return true
+	case r >= 0x370 && r <= 0x37D, r >= 0x37F && r <= 0x1FFF:
+		return true
+	case r >= 0x200C && r <= 0x200D, r >= 0x2070 && r <= 0x218F:
+		return true
+	case r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF:
+		return true
+	case r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD:
+		return true
+	case r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNameChar reports whether r may appear anywhere in an XML Name after
+// its first character, per the NameChar production.
+func isNameChar(r rune) bool {
+	if isNameStartChar(r) {
+		return true
+	}
+	switch {
+	case r == '-' || r == '.':
+		return true
+	case r >= '0' && r <= '9':
+		return true
+	case r == 0xB7:
+		return true
+	case r >= 0x0300 && r <= 0x036F:
+		return true
+	case r == 0x203F || r == 0x2040:
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidXMLChar reports whether r may legally appear in an XML 1.0
+// document. It rejects the C0 control characters other than tab, newline
+// and carriage return, lone surrogate halves, and the BMP non-characters.
+func isValidXMLChar(r rune) bool {
+	switch {
+	case r == 0x9 || r == 0xA || r == 0xD:
+		return true
+	case r >= 0x20 && r <= 0xD7FF:
+		return true
+	case r >= 0xE000 && r <= 0xFFFD:
+		return true
+	case r >= 0x10000 && r <= 0x10FFFF:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateName checks word against the XML Name production: a
+// NameStartChar followed by zero or more NameChars.
+func validateName(word []rune, pos Position) error {
+	if len(word) == 0 {
+		return newSyntaxError(pos, "", "a non-empty name")
+	}
+	if !isNameStartChar(word[0]) {
+		return newSyntaxError(pos, string(word[0]), "a valid XML NameStartChar")
+	}
+	for _, r := range word[1:] {
+		if !isNameChar(r) {
+			return newSyntaxError(pos, string(r), "a valid XML NameChar")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package rdfloader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxEntityExpansions is used when XMLReader.MaxEntityExpansions is
+// left at its zero value.
+const DefaultMaxEntityExpansions = 100000
+
+// predefinedEntities are the five entities every XML document may use
+// without declaring them.
+var predefinedEntities = map[string]string{
+	"amp":  "&",
+	"lt":   "<",
+	"gt":   ">",
+	"apos": "'",
+	"quot": "\"",
+}
+
+func (xmlReader *XMLReader) maxEntityExpansions() int {
+	if xmlReader.MaxEntityExpansions > 0 {
+		return xmlReader.MaxEntityExpansions
+	}
+	return DefaultMaxEntityExpansions
+}
+
+// expandEntities replaces every &name; and &#ref; occurring in raw with
+// its expansion, recursively expanding user-declared entities. It enforces
+// MaxEntityExpansions across the whole document, not just the current
+// call, so a handful of nested entities can't blow up into gigabytes of
+// output.
+func (xmlReader *XMLReader) expandEntities(raw []rune) (string, error) {
+	var out []rune
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '&' {
+			out = append(out, raw[i])
+			continue
+		}
+
+		end := -1
+		for j := i + 1; j < len(raw); j++ {
+			if raw[j] == ';' {
+				end = j
+				break
+			}
+		}
+		if end == -1 {
+			return "", newSyntaxError(xmlReader.position(), string(raw[i:]), "a ';' terminating the entity or character reference")
+		}
+
+		xmlReader.entityExpansions++
+		if xmlReader.entityExpansions > xmlReader.maxEntityExpansions() {
+			return "", fmt.Errorf("rdfloader: entity expansion limit (%d) exceeded, possible billion-laughs attack", xmlReader.maxEntityExpansions())
+		}
+
+		expanded, err := xmlReader.resolveEntity(string(raw[i+1 : end]))
+		if err != nil {
+			return "", err
+		}
+		out = append(out, []rune(expanded)...)
+		i = end
+	}
+	return string(out), nil
+}
+
+// resolveEntity returns the replacement text for the reference named by
+// ref, the part of "&ref;" between the ampersand and the semicolon.
+func (xmlReader *XMLReader) resolveEntity(ref string) (string, error) {
+	if strings.HasPrefix(ref, "#x") || strings.HasPrefix(ref, "#X") {
+		codepoint, err := strconv.ParseInt(ref[2:], 16, 32)
+		if err != nil {
+			return "", newSyntaxError(xmlReader.position(), ref, "a hexadecimal character reference")
+		}
+		return string(rune(codepoint)), nil
+	}
+	if strings.HasPrefix(ref, "#") {
+		codepoint, err := strconv.ParseInt(ref[1:], 10, 32)
+		if err != nil {
+			return "", newSyntaxError(xmlReader.position(), ref, "a decimal character reference")
+		}
+		return string(rune(codepoint)), nil
+	}
+	if value, ok := predefinedEntities[ref]; ok {
+		return value, nil
+	}
+	if value, ok := xmlReader.entities[ref]; ok {
+		// entity values may themselves reference other entities.
+		return xmlReader.expandEntities([]rune(value))
+	}
+	return "", fmt.Errorf("rdfloader: reference to undefined entity %q", ref)
+}
+
+// readComment consumes a "<!-- ... -->" comment, returning its text.
+func (xmlReader *XMLReader) readComment() (string, error) {
+	if _, err := xmlReader.readNBytes(4); err != nil { // "<!--"
+		return "", err
+	}
+	var out []rune
+	for {
+		if next3, err := xmlReader.peekNBytes(3); err == nil && string(next3) == "-->" {
+			_, err := xmlReader.readNBytes(3)
+			return string(out), err
+		}
+		r, err := xmlReader.readARune()
+		if err != nil {
+			return string(out), err
+		}
+		out = append(out, r)
+	}
+}
+
+// readCDATA consumes a "<![CDATA[ ... ]]>" section, returning its content
+// verbatim, with no entity expansion or further interpretation.
+func (xmlReader *XMLReader) readCDATA() (string, error) {
+	if _, err := xmlReader.readNBytes(9); err != nil { // "<![CDATA["
+		return "", err
+	}
+	var out []rune
+	for {
+		if next3, err := xmlReader.peekNBytes(3); err == nil && string(next3) == "]]>" {
+			_, err := xmlReader.readNBytes(3)
+			return string(out), err
+		}
+		r, err := xmlReader.readARune()
+		if err != nil {
+			return string(out), err
+		}
+		out = append(out, r)
+	}
+}
+
+// readDoctype consumes a "<!DOCTYPE root [ internal subset ] >" declaration,
+// populating xmlReader.entities from any <!ENTITY> declarations found in
+// the internal subset.
+func (xmlReader *XMLReader) readDoctype() error {
+	if _, err := xmlReader.readNBytes(9); err != nil { // "<!DOCTYPE"
+		return err
+	}
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return err
+	}
+	// skip over the root element name. '[' (the internal subset's opening
+	// bracket) doesn't fit readTill's delimiter bitmask, which only covers
+	// runes below 64, so it's handled as an explicit stop rune instead.
+	if _, err := xmlReader.readTillRune(WHITESPACE|1<<'>', '['); err != nil {
+		return err
+	}
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return err
+	}
+
+	nextRune, err := xmlReader.peekARune()
+	if err != nil {
+		return err
+	}
+	if nextRune == '[' {
+		xmlReader.readARune()
+		if err := xmlReader.readInternalSubset(); err != nil {
+			return err
+		}
+		if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+			return err
+		}
+	}
+
+	pos := xmlReader.position()
+	closeRune, err := xmlReader.readARune()
+	if err != nil {
+		return err
+	}
+	if closeRune != '>' {
+		return newSyntaxError(pos, string(closeRune), "a '>' closing the DOCTYPE declaration")
+	}
+	return nil
+}
+
+// readInternalSubset reads the declarations between a DOCTYPE's '[' and
+// ']', storing any entity declarations found on xmlReader.entities.
+func (xmlReader *XMLReader) readInternalSubset() error {
+	if xmlReader.entities == nil {
+		xmlReader.entities = map[string]string{}
+	}
+	for {
+		if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+			return err
+		}
+
+		pos := xmlReader.position()
+		nextRune, err := xmlReader.peekARune()
+		if err != nil {
+			return err
+		}
+		if nextRune == ']' {
+			xmlReader.readARune()
+			return nil
+		}
+
+		nextTwo, err := xmlReader.peekNBytes(2)
+		if err != nil {
+			return err
+		}
+		if string(nextTwo) != "<!" {
+			return newSyntaxError(pos, string(nextTwo), "an <!ENTITY declaration or ']' closing the internal subset")
+		}
+		if err := xmlReader.readEntityDecl(); err != nil {
+			return err
+		}
+	}
+}
+
+// readEntityDecl consumes a single <!ENTITY name "value"> declaration.
+func (xmlReader *XMLReader) readEntityDecl() error {
+	pos := xmlReader.position()
+	keyword, err := xmlReader.readTill(WHITESPACE)
+	if err != nil {
+		return err
+	}
+	if string(keyword) != "<!ENTITY" {
+		return newSyntaxError(pos, string(keyword), "an <!ENTITY declaration")
+	}
+
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return err
+	}
+	name, err := xmlReader.readTill(WHITESPACE)
+	if err != nil {
+		return err
+	}
+	if err := validateName(name, pos); err != nil {
+		return err
+	}
+
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return err
+	}
+	pos = xmlReader.position()
+	quote, err := xmlReader.readARune()
+	if err != nil {
+		return err
+	}
+	if quote != '\'' && quote != '"' {
+		return newSyntaxError(pos, string(quote), "the entity value enclosed in quotes")
+	}
+
+	value, err := xmlReader.readTill(1 << uint(quote))
+	if err != nil {
+		return err
+	}
+	xmlReader.readARune() // closing quote
+
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return err
+	}
+	pos = xmlReader.position()
+	closeRune, err := xmlReader.readARune()
+	if err != nil {
+		return err
+	}
+	if closeRune != '>' {
+		return newSyntaxError(pos, string(closeRune), "a '>' closing the ENTITY declaration")
+	}
+
+	xmlReader.entities[string(name)] = string(value)
+	return nil
+}
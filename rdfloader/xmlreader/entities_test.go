@@ -0,0 +1,87 @@
+package rdfloader
+
+import "testing"
+
+// TestRead_ExpandsDeclaredAndPredefinedEntities checks that both the five
+// predefined entities and a document-declared <!ENTITY> are expanded in
+// element text.
+func TestRead_ExpandsDeclaredAndPredefinedEntities(t *testing.T) {
+	reader := readerFromString(`<?xml version="1.0"?>
+<!DOCTYPE rdf:RDF [
+  <!ENTITY company "Example &amp; Co">
+]>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<rdf:Description rdf:about="http://example.org/a">
+  <dc:publisher>&company;</dc:publisher>
+</rdf:Description>
+</rdf:RDF>`)
+
+	block, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	publisher := block.Children[0].Children[0]
+	if want := "Example & Co"; publisher.Value != want {
+		t.Fatalf("got publisher %q, want %q", publisher.Value, want)
+	}
+}
+
+// TestRead_CDATAIsNotEntityExpanded checks that CDATA content is carried
+// through verbatim even when it looks like markup or an entity reference.
+func TestRead_CDATAIsNotEntityExpanded(t *testing.T) {
+	reader := readerFromString(`<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<rdf:Description rdf:about="http://example.org/a">
+  <dc:description><![CDATA[<b>not a tag</b> & not an entity]]></dc:description>
+</rdf:Description>
+</rdf:RDF>`)
+
+	block, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	description := block.Children[0].Children[0]
+	want := "<b>not a tag</b> & not an entity"
+	if description.Value != want {
+		t.Fatalf("got description %q, want %q", description.Value, want)
+	}
+}
+
+// TestRead_IgnoresCommentsInsideElements checks that a comment nested
+// inside an element's content is dropped rather than becoming part of its
+// value or a child block.
+func TestRead_IgnoresCommentsInsideElements(t *testing.T) {
+	reader := readerFromString(`<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<rdf:Description rdf:about="http://example.org/a">
+  <dc:title><!-- TODO: localize -->Hello</dc:title>
+</rdf:Description>
+</rdf:RDF>`)
+
+	block, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	title := block.Children[0].Children[0]
+	if title.Value != "Hello" {
+		t.Fatalf("got title %q, want %q", title.Value, "Hello")
+	}
+}
+
+// TestExpandEntities_RejectsEntityExpansionBomb checks that
+// MaxEntityExpansions caps runaway nested entity expansion instead of
+// letting it blow up into unbounded output.
+func TestExpandEntities_RejectsEntityExpansionBomb(t *testing.T) {
+	reader := readerFromString("")
+	reader.MaxEntityExpansions = 2
+	reader.entities = map[string]string{
+		"a": "&b;&b;",
+		"b": "&c;&c;",
+		"c": "x",
+	}
+
+	_, err := reader.expandEntities([]rune("&a;"))
+	if err == nil {
+		t.Fatal("expected an error once the expansion limit was exceeded")
+	}
+}
@@ -0,0 +1,115 @@
+package rdfloader
+
+import "io"
+
+// EventType identifies the kind of token produced by XMLReader.NextEvent.
+type EventType int
+
+const (
+	EventEOF EventType = iota
+	EventProlog
+	EventStartTag
+	EventEndTag
+	EventText
+	EventComment
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventEOF:
+		return "EOF"
+	case EventProlog:
+		return "Prolog"
+	case EventStartTag:
+		return "StartTag"
+	case EventEndTag:
+		return "EndTag"
+	case EventText:
+		return "Text"
+	case EventComment:
+		return "Comment"
+	default:
+		return "Unknown"
+	}
+}
+
+// Position marks a location in the source document.
+type Position struct {
+	Line, Column, Offset int
+}
+
+// Event is a single token pulled off the stream by XMLReader.NextEvent.
+// Depending on Type, only the matching field is populated: Tag for
+// EventStartTag/EventEndTag, Text for EventText, Comment for EventComment.
+type Event struct {
+	Type        EventType
+	Tag         Tag
+	Text        string
+	Comment     string
+	SelfClosing bool // set on EventStartTag when the tag was written as <tag/>
+	Pos         Position
+}
+
+// NextEvent pulls the next token off the document without materializing a
+// tree, so callers can walk documents far bigger than memory allows. It is
+// resumable: each call picks up exactly where the previous one left the
+// file pointer, and it is safe to stop calling it at any point and let the
+// underlying reader be garbage collected.
+func (xmlReader *XMLReader) NextEvent() (Event, error) {
+	pos := xmlReader.position()
+
+	if _, err := xmlReader.ignoreWhiteSpace(); err != nil {
+		return Event{Type: EventEOF, Pos: pos}, err
+	}
+
+	if nextTwo, err := xmlReader.peekNBytes(2); err == nil && string(nextTwo) == "</" {
+		closingTag, err := xmlReader.readClosingTag()
+		return Event{Type: EventEndTag, Tag: closingTag, Pos: pos}, err
+	}
+
+	if next4, err := xmlReader.peekNBytes(4); err == nil && string(next4) == "<!--" {
+		comment, err := xmlReader.readComment()
+		return Event{Type: EventComment, Comment: comment, Pos: pos}, err
+	}
+
+	if next9, err := xmlReader.peekNBytes(9); err == nil && string(next9) == "<![CDATA[" {
+		text, err := xmlReader.readCDATA()
+		return Event{Type: EventText, Text: text, Pos: pos}, err
+	}
+
+	if next9, err := xmlReader.peekNBytes(9); err == nil && string(next9) == "<!DOCTYPE" {
+		if err := xmlReader.readDoctype(); err != nil {
+			return Event{Pos: pos}, err
+		}
+		return xmlReader.NextEvent()
+	}
+
+	nextRune, err := xmlReader.peekARune()
+	if err != nil {
+		if err == io.EOF {
+			return Event{Type: EventEOF, Pos: pos}, io.EOF
+		}
+		return Event{Pos: pos}, err
+	}
+
+	if nextRune != '<' {
+		word, err := xmlReader.readTill(1 << '<')
+		if err != nil {
+			return Event{Type: EventText, Pos: pos}, err
+		}
+		text, expandErr := xmlReader.expandEntities(word)
+		if expandErr != nil {
+			return Event{Type: EventText, Pos: pos}, expandErr
+		}
+		return Event{Type: EventText, Text: text, Pos: pos}, nil
+	}
+
+	openingTag, isProlog, blockComplete, err := xmlReader.readOpeningTag()
+	if err != nil {
+		return Event{Pos: pos}, err
+	}
+	if isProlog {
+		return Event{Type: EventProlog, Pos: pos}, nil
+	}
+	return Event{Type: EventStartTag, Tag: openingTag, SelfClosing: blockComplete, Pos: pos}, nil
+}
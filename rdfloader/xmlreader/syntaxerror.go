@@ -0,0 +1,23 @@
+package rdfloader
+
+import "fmt"
+
+// SyntaxError reports a well-formedness violation found while reading an
+// rdf/xml document, such as an illegal character, an unexpected token, or
+// a name that doesn't conform to the XML character classes.
+type SyntaxError struct {
+	Pos      Position
+	Token    string
+	Expected string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%d:%d: expected %s", e.Pos.Line+1, e.Pos.Column+1, e.Expected)
+	}
+	return fmt.Sprintf("%d:%d: unexpected %q, expected %s", e.Pos.Line+1, e.Pos.Column+1, e.Token, e.Expected)
+}
+
+func newSyntaxError(pos Position, token, expected string) error {
+	return &SyntaxError{Pos: pos, Token: token, Expected: expected}
+}
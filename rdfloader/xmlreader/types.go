@@ -0,0 +1,198 @@
+package rdfloader
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// WHITESPACE is a bitmask, indexed by rune value, of the ASCII characters
+// that are treated as blank space between tokens: space, tab, \n and \r.
+const WHITESPACE = uint64(1)<<' ' | uint64(1)<<'\t' | uint64(1)<<'\n' | uint64(1)<<'\r'
+
+// pair is the two components of a colon-separated token, e.g. the
+// "rdf:about" in an attribute name or the "spdx:License" in a tag name.
+// first/second are interface{} rather than string so that readColonPair's
+// callers can tell "no colon found" (only first is set) apart from a pair.
+type pair struct {
+	first, second interface{}
+}
+
+// Attribute is a single name/value pair found inside an opening tag,
+// optionally namespaced. SchemaName is empty when no prefix was used.
+type Attribute struct {
+	SchemaName string
+	Name       string
+	Value      string
+}
+
+// Tag is the schema-qualified name of an opening or closing tag, together
+// with the attributes declared on it.
+type Tag struct {
+	SchemaName string
+	Name       string
+	Attrs      []Attribute
+}
+
+// Block is one fully parsed XML element: its opening tag, the text found
+// directly inside it, and any child elements.
+type Block struct {
+	OpeningTag Tag
+	Value      string
+	Children   []*Block
+	// InnerXML is the element's content reserialized as XML markup, in
+	// document order - unlike Value (text only) and Children (elements
+	// only), it preserves how text and child elements interleave. Used for
+	// rdf:parseType="Literal", whose object is the element's XML content
+	// itself rather than its concatenated text.
+	InnerXML string
+}
+
+// XMLReader reads an rdf/xml document one tag at a time.
+type XMLReader struct {
+	fileReader *bufio.Reader
+	fileObj    *os.File
+
+	// position of the next unread rune. Used to annotate events and
+	// errors with where they came from in the source document.
+	line, column, offset int
+
+	// entities holds the <!ENTITY name "value"> declarations collected
+	// from the document's internal DOCTYPE subset, used to expand
+	// &name; references found later in the document.
+	entities map[string]string
+
+	// MaxEntityExpansions caps the total number of entity and character
+	// references a document is allowed to expand, guarding against
+	// billion-laughs style exponential blowup. Zero selects
+	// DefaultMaxEntityExpansions.
+	MaxEntityExpansions int
+	entityExpansions    int
+}
+
+// position returns the position of the next rune to be read.
+func (xmlReader *XMLReader) position() Position {
+	return Position{Line: xmlReader.line, Column: xmlReader.column, Offset: xmlReader.offset}
+}
+
+// advance updates the reader's position bookkeeping after consuming r.
+func (xmlReader *XMLReader) advance(r rune) {
+	xmlReader.offset += utf8.RuneLen(r)
+	if r == '\n' {
+		xmlReader.line++
+		xmlReader.column = 0
+	} else {
+		xmlReader.column++
+	}
+}
+
+func (xmlReader *XMLReader) readARune() (rune, error) {
+	r, _, err := xmlReader.fileReader.ReadRune()
+	if err != nil {
+		return r, err
+	}
+	xmlReader.advance(r)
+	return r, nil
+}
+
+func (xmlReader *XMLReader) peekARune() (rune, error) {
+	b, err := xmlReader.fileReader.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	if b[0] < utf8.RuneSelf {
+		return rune(b[0]), nil
+	}
+	b, err = xmlReader.fileReader.Peek(utf8.UTFMax)
+	if len(b) == 0 {
+		return 0, err
+	}
+	r, _ := utf8.DecodeRune(b)
+	return r, nil
+}
+
+func (xmlReader *XMLReader) readNBytes(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	read, err := io.ReadFull(xmlReader.fileReader, buf)
+	for _, b := range buf[:read] {
+		xmlReader.advance(rune(b))
+	}
+	return buf, err
+}
+
+func (xmlReader *XMLReader) peekNBytes(n int) ([]byte, error) {
+	return xmlReader.fileReader.Peek(n)
+}
+
+// ignoreWhiteSpace consumes runes from the stream until a non-blank rune
+// is found, returning how many were skipped.
+func (xmlReader *XMLReader) ignoreWhiteSpace() (count int, err error) {
+	for {
+		r, peekErr := xmlReader.peekARune()
+		if peekErr != nil {
+			if count == 0 {
+				return count, peekErr
+			}
+			return count, nil
+		}
+		if r >= 64 || (uint64(1)<<uint(r))&WHITESPACE == 0 {
+			return count, nil
+		}
+		if _, err := xmlReader.readARune(); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// readTill accumulates runes until one of them is set in the delim bitmask,
+// leaving that rune unconsumed so the caller can inspect it. Every rune it
+// consumes is validated against the XML 1.0 character classes, so callers
+// never see illegal control characters or lone surrogate halves.
+func (xmlReader *XMLReader) readTill(delim uint64) (word []rune, err error) {
+	for {
+		pos := xmlReader.position()
+		r, peekErr := xmlReader.peekARune()
+		if peekErr != nil {
+			return word, peekErr
+		}
+		if r < 64 && (uint64(1)<<uint(r))&delim != 0 {
+			return word, nil
+		}
+		if !isValidXMLChar(r) {
+			return word, newSyntaxError(pos, string(r), "a character permitted by the XML spec")
+		}
+		if _, err := xmlReader.readARune(); err != nil {
+			return word, err
+		}
+		word = append(word, r)
+	}
+}
+
+// readTillRune is readTill with extra stop runes that don't fit the delim
+// bitmask because their value is 64 or above (e.g. '[').
+func (xmlReader *XMLReader) readTillRune(delim uint64, stop ...rune) (word []rune, err error) {
+	for {
+		pos := xmlReader.position()
+		r, peekErr := xmlReader.peekARune()
+		if peekErr != nil {
+			return word, peekErr
+		}
+		if r < 64 && (uint64(1)<<uint(r))&delim != 0 {
+			return word, nil
+		}
+		for _, s := range stop {
+			if r == s {
+				return word, nil
+			}
+		}
+		if !isValidXMLChar(r) {
+			return word, newSyntaxError(pos, string(r), "a character permitted by the XML spec")
+		}
+		if _, err := xmlReader.readARune(); err != nil {
+			return word, err
+		}
+		word = append(word, r)
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 func (xmlReader *XMLReader) readColonPair(delim uint64) (pair pair, colonFound bool, err error) {
 	// reads a:b into a Pair Object.
+	pos := xmlReader.position()
 	word, err := xmlReader.readTill(delim)
 	if err != nil {
 		return
@@ -24,18 +26,28 @@ func (xmlReader *XMLReader) readColonPair(delim uint64) (pair pair, colonFound b
 	for i, r := range word {
 		if r == ':' {
 			colonFound = true
-			pair.first = string(word[:i])
-			latter := string(word[i+1:])
+			first := word[:i]
+			latter := word[i+1:]
 			if len(latter) == 0 {
-				err = errors.New("expected a word after colon")
+				err = newSyntaxError(pos, string(word), "a name after the colon")
 				return
 			}
-			pair.second = latter
+			if err = validateName(first, pos); err != nil {
+				return
+			}
+			if err = validateName(latter, pos); err != nil {
+				return
+			}
+			pair.first = string(first)
+			pair.second = string(latter)
 			break
 		}
 	}
 	if !colonFound {
 		// no colon was found.
+		if err = validateName(word, pos); err != nil {
+			return
+		}
 		pair.first = string(word)
 	}
 	return
@@ -59,17 +71,22 @@ func (xmlReader *XMLReader) readAttribute() (attr Attribute, err error) {
 		return
 	}
 
+	pos := xmlReader.position()
 	nextRune, err := xmlReader.readARune()
 	if err != nil {
 		return attr, err
 	}
 	if nextRune != '=' {
-		err = errors.New("expected an assignment sign (=)")
+		return attr, newSyntaxError(pos, string(nextRune), "an assignment sign (=)")
 	}
 
+	pos = xmlReader.position()
 	firstQuote, err := xmlReader.readARune()
+	if err != nil {
+		return attr, err
+	}
 	if !(firstQuote == '\'' || firstQuote == '"') {
-		err = errors.New("assignment operator must be followed by an attribute enclosed within quotes")
+		return attr, newSyntaxError(pos, string(firstQuote), "an attribute value enclosed in quotes")
 	}
 
 	// read till next quote or a blank character.
@@ -78,12 +95,17 @@ func (xmlReader *XMLReader) readAttribute() (attr Attribute, err error) {
 		return attr, err
 	}
 
+	pos = xmlReader.position()
 	secondQuote, _ := xmlReader.readARune()
 	if firstQuote != secondQuote {
-		return attr, errors.New("unexpected blank char. expected a closing quote")
+		return attr, newSyntaxError(pos, string(secondQuote), "a closing quote matching the opening one")
 	}
 
-	attr.Value = string(word)
+	value, err := xmlReader.expandEntities(word)
+	if err != nil {
+		return attr, err
+	}
+	attr.Value = value
 	return attr, nil
 }
 
@@ -111,31 +133,33 @@ func (xmlReader *XMLReader) readOpeningTag() (tag Tag, isProlog, blockComplete b
 	// after stripping all the spaces, the next character should be '<'
 	//   If the next character is not '<',
 	//       there are few chars before opening tag. Which is not allowed!
+	pos := xmlReader.position()
 	word, err = xmlReader.readTill(1 << '<')
 	if err == io.EOF {
 		// we reached the end of the file while searching for a new tag.
 		if len(word) > 0 {
-			return tag, isProlog, blockComplete, errors.New("found stray characters at EOF")
+			return tag, isProlog, blockComplete, newSyntaxError(pos, string(word), "a '<' starting the next tag")
 		} else {
 			// no new tags were found.
 			return tag, isProlog, blockComplete, io.EOF
 		}
 	}
 	if len(word) != 0 {
-		return tag, isProlog, blockComplete, errors.New("found extra chars before tag start")
+		return tag, isProlog, blockComplete, newSyntaxError(pos, string(word), "a '<' starting the next tag")
 	}
 
 	// next char is '<'.
 	xmlReader.readARune()
 	xmlReader.ignoreWhiteSpace() // there shouldn't be any spaces in a well-formed rdf/xml document.
 
+	pos = xmlReader.position()
 	nextRune, err := xmlReader.peekARune()
 	if err != nil {
 		return
 	}
 
 	if nextRune == '/' {
-		return tag, isProlog, blockComplete, errors.New("unexpected closing tag")
+		return tag, isProlog, blockComplete, newSyntaxError(pos, string(nextRune), "an opening tag, not a closing one")
 	}
 	if nextRune == '?' {
 		// a prolog is found.
@@ -164,7 +188,7 @@ func (xmlReader *XMLReader) readOpeningTag() (tag Tag, isProlog, blockComplete b
 			xmlReader.readARune()
 			return tag, isProlog, blockComplete, err
 		}
-		err = fmt.Errorf("expected a > char after ?. Found %v", nextRune)
+		err = newSyntaxError(pos, string(nextRune), "a '>' closing the prolog")
 	}
 
 	// reading the next word till we reach a colon or a blank-char or a closing angular bracket.
@@ -198,13 +222,14 @@ func (xmlReader *XMLReader) readOpeningTag() (tag Tag, isProlog, blockComplete b
 		xmlReader.readARune()
 		blockComplete = true
 
+		pos := xmlReader.position()
 		nextRune, err := xmlReader.readARune()
 		if err != nil {
 			return tag, isProlog, blockComplete, err
 		}
 
 		if nextRune != '>' {
-			err = errors.New("expected closing angular bracket after /")
+			err = newSyntaxError(pos, string(nextRune), "a '>' closing the self-closing tag")
 		}
 		return tag, isProlog, blockComplete, err
 	}
@@ -254,13 +279,14 @@ func (xmlReader *XMLReader) readOpeningTag() (tag Tag, isProlog, blockComplete b
 		// "<[schemaName:]tag /" was parsed. expecting next character to be a closing angular bracket.
 		blockComplete = true
 
+		pos := xmlReader.position()
 		nextRune, err := xmlReader.readARune()
 		if err != nil {
 			return tag, isProlog, blockComplete, err
 		}
 
 		if nextRune != '>' {
-			err = errors.New("expected closing angular bracket after /")
+			err = newSyntaxError(pos, string(nextRune), "a '>' closing the self-closing tag")
 		}
 	}
 	return tag, isProlog, blockComplete, err
@@ -302,70 +328,148 @@ func (xmlReader *XMLReader) readClosingTag() (closingTag Tag, err error) {
 }
 
 func (xmlReader *XMLReader) readBlock() (block Block, err error) {
-	openingTag, isProlog, blockComplete, err := xmlReader.readOpeningTag()
-	if isProlog {
-		return xmlReader.readBlock()
+	// pull events until we find the start tag this block is built from,
+	// skipping over any prolog or comments along the way.
+	event, err := xmlReader.NextEvent()
+	for err == nil && (event.Type == EventProlog || event.Type == EventComment) {
+		event, err = xmlReader.NextEvent()
 	}
 	if err != nil {
-		return
-	}
-	block.OpeningTag = openingTag
-
-	if blockComplete {
-		// tag was of this type: <schemaName:tagName />
 		return block, err
 	}
+	if event.Type != EventStartTag {
+		return block, fmt.Errorf("expected a start tag, found a %v event", event.Type)
+	}
+	return xmlReader.buildBlock(event, false)
+}
 
-	xmlReader.ignoreWhiteSpace()
+// HasLiteralParseType reports whether tag syntactically declares
+// rdf:parseType="Literal" - just by attribute name/value, without
+// resolving namespace prefixes (the full rdf:parseType semantics, prefix
+// included, are resolved later by the parser package). It's used only to
+// decide whether buildBlock needs to pay the cost of reconstructing
+// InnerXML for a subtree; under-resolving here only wastes a little work,
+// it can never silently change what gets parsed as a literal.
+func HasLiteralParseType(tag Tag) bool {
+	for _, attr := range tag.Attrs {
+		if attr.Name == "parseType" && attr.Value == "Literal" {
+			return true
+		}
+	}
+	return false
+}
 
-	// <schemaName:tagName [attributes] > is read till now.
-	nextRune, err := xmlReader.peekARune()
-	if err != nil {
-		return
+// buildBlock drains events from the pull layer until the block opened by
+// startEvent is closed, assembling the same tree shape the tree-based
+// reader has always produced. This is how readBlock/Read keep working
+// unchanged even though the reader itself is now a streaming, event-based
+// parser under the hood.
+//
+// captureInnerXML is true while building the subtree of an
+// rdf:parseType="Literal" property element (including the element itself),
+// and propagates to every descendant once set. InnerXML is only ever read
+// off that one block, so outside of it the accumulation is skipped: doing
+// it unconditionally made every block in the document pay an O(n) string
+// append on every child, turning parsing of a root with k top-level
+// subjects into O(k^2) work for a value nothing would read.
+func (xmlReader *XMLReader) buildBlock(startEvent Event, captureInnerXML bool) (block Block, err error) {
+	block.OpeningTag = startEvent.Tag
+	captureInnerXML = captureInnerXML || HasLiteralParseType(startEvent.Tag)
+	if startEvent.SelfClosing {
+		// tag was of this type: <schemaName:tagName />
+		return block, nil
 	}
 
-	if nextRune != '<' {
-		// the tag must be wrapping a string resource within it.
-		// tag is of type <schemaName:tagName> value </schemaName:tagName>
-		word, err := xmlReader.readTill(1 << '<') // according to the example, word=value.
-		if err != nil {
-			return block, err
-		}
-		block.Value = string(word)
-	} else {
-		// expecting a new tag or closing tag of the currently read tag.
-		nextTwoBytes, err := xmlReader.peekNBytes(2)
+	for {
+		event, err := xmlReader.NextEvent()
 		if err != nil {
 			return block, err
 		}
 
-		// while we don't get a closing tag, read the children.
-		for string(nextTwoBytes) != "</" {
-			// a new tag is found.
-			childBlock, err := xmlReader.readBlock()
-			if err != nil {
-				return block, err
+		switch event.Type {
+		case EventText:
+			// tag is of type <schemaName:tagName> value </schemaName:tagName>
+			block.Value += event.Text
+			if captureInnerXML {
+				block.InnerXML += EscapeXMLText(event.Text)
 			}
 
-			block.Children = append(block.Children, &childBlock)
+		case EventComment:
+			continue
 
-			xmlReader.ignoreWhiteSpace()
-			nextTwoBytes, err = xmlReader.peekNBytes(2)
+		case EventStartTag:
+			childBlock, err := xmlReader.buildBlock(event, captureInnerXML)
 			if err != nil {
 				return block, err
 			}
+			block.Children = append(block.Children, &childBlock)
+			if captureInnerXML {
+				block.InnerXML += RenderElement(&childBlock)
+			}
+
+		case EventEndTag:
+			if event.Tag.Name != startEvent.Tag.Name || event.Tag.SchemaName != startEvent.Tag.SchemaName {
+				// opening and closing tags are not same.
+				return block, fmt.Errorf("opening and closing tags doesn't match: opening tag; %v:%v, closing tag: %v:%v.", startEvent.Tag.SchemaName, startEvent.Tag.Name, event.Tag.SchemaName, event.Tag.Name)
+			}
+			return block, nil
+
+		default:
+			return block, fmt.Errorf("unexpected %v event inside a block", event.Type)
 		}
 	}
+}
 
-	closingTag, err := xmlReader.readClosingTag()
-	if err != nil {
-		return block, err
+// RenderElement reserializes block's own opening tag and content back into
+// XML markup, for use as a child's contribution to its parent's InnerXML.
+func RenderElement(block *Block) string {
+	opening := renderOpeningTag(block.OpeningTag)
+	if block.InnerXML == "" {
+		return opening + "/>"
+	}
+	return opening + ">" + block.InnerXML + "</" + qualifiedName(block.OpeningTag) + ">"
+}
+
+// renderOpeningTag reserializes tag and its attributes as the opening half
+// of an XML start tag, e.g. `<dc:creator xml:lang="en"`.
+func renderOpeningTag(tag Tag) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(qualifiedName(tag))
+	for _, attr := range tag.Attrs {
+		name := attr.Name
+		if attr.SchemaName != "" {
+			name = attr.SchemaName + ":" + name
+		}
+		b.WriteByte(' ')
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeXMLAttr(attr.Value))
+		b.WriteByte('"')
 	}
-	if openingTag.Name != closingTag.Name || openingTag.SchemaName != closingTag.SchemaName {
-		// opening and closing tags are not same.
-		return block, fmt.Errorf("opening and closing tags doesn't match: opening tag; %v:%v, closing tag: %v:%v.", openingTag.SchemaName, openingTag.Name, closingTag.SchemaName, closingTag.Name)
+	return b.String()
+}
+
+// qualifiedName joins tag's schema prefix and name back into "prefix:name",
+// or just "name" for an unprefixed tag.
+func qualifiedName(tag Tag) string {
+	if tag.SchemaName == "" {
+		return tag.Name
 	}
-	return block, err
+	return tag.SchemaName + ":" + tag.Name
+}
+
+var xmlTextReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+var xmlAttrReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;")
+
+// EscapeXMLText escapes s for use as XML character data.
+func EscapeXMLText(s string) string {
+	return xmlTextReplacer.Replace(s)
+}
+
+// escapeXMLAttr escapes s for use inside a double-quoted XML attribute value.
+func escapeXMLAttr(s string) string {
+	return xmlAttrReplacer.Replace(s)
 }
 
 func (xmlReader *XMLReader) Read() (rootBlock Block, err error) {
@@ -378,7 +482,7 @@ func (xmlReader *XMLReader) Read() (rootBlock Block, err error) {
 
 func XMLReaderFromFileObject(fileObject *bufio.Reader) XMLReader {
 	// user will be responsible for closing the file.
-	return XMLReader{fileObject, nil}
+	return XMLReader{fileReader: fileObject}
 }
 
 func XMLReaderFromFilePath(filePath string) (xmlReader XMLReader, err error) {
@@ -0,0 +1,70 @@
+package rdfloader
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func readerFromString(s string) XMLReader {
+	return XMLReaderFromFileObject(bufio.NewReader(strings.NewReader(s)))
+}
+
+// TestRead_SkipsCommentsBeforeRoot guards against readBlock treating a
+// comment preceding the root element as an error.
+func TestRead_SkipsCommentsBeforeRoot(t *testing.T) {
+	reader := readerFromString(`<?xml version="1.0"?>
+<!-- license header -->
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="http://example.org/a"/>
+</rdf:RDF>`)
+
+	block, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if block.OpeningTag.SchemaName != "rdf" || block.OpeningTag.Name != "RDF" {
+		t.Fatalf("got root tag %v:%v, want rdf:RDF", block.OpeningTag.SchemaName, block.OpeningTag.Name)
+	}
+	if len(block.Children) != 1 {
+		t.Fatalf("got %d children, want 1", len(block.Children))
+	}
+}
+
+// TestReadTill_RejectsIllegalControlCharacter checks that an illegal XML
+// character is reported as a SyntaxError at its own position, not at the
+// start of the token.
+func TestReadTill_RejectsIllegalControlCharacter(t *testing.T) {
+	reader := readerFromString("bad\x01text<")
+
+	_, err := reader.readTill(1 << '<')
+	if err == nil {
+		t.Fatal("expected a syntax error for an illegal control character")
+	}
+	synErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("got error of type %T, want *SyntaxError", err)
+	}
+	if synErr.Pos.Column != 3 {
+		t.Fatalf("got column %d, want 3 (the position of the illegal character)", synErr.Pos.Column)
+	}
+}
+
+// TestReadTill_TracksLineAndColumn checks that position bookkeeping
+// advances across newlines the way syntax errors rely on to report useful
+// line:column pairs.
+func TestReadTill_TracksLineAndColumn(t *testing.T) {
+	reader := readerFromString("ab\ncd\x01<")
+
+	_, err := reader.readTill(1 << '<')
+	if err == nil {
+		t.Fatal("expected a syntax error for an illegal control character")
+	}
+	synErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("got error of type %T, want *SyntaxError", err)
+	}
+	if synErr.Pos.Line != 1 || synErr.Pos.Column != 2 {
+		t.Fatalf("got %d:%d, want 1:2 (second line, third column)", synErr.Pos.Line, synErr.Pos.Column)
+	}
+}
@@ -0,0 +1,131 @@
+/**
+ * This module writes a Parser's triples back out as N-Triples, the
+ * simplest of the RDF serializations: one "subject predicate object ."
+ * line per triple, with no namespace bookkeeping required.
+ */
+package serializer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/RishabhBhatnagar/gordf/rdfloader/parser"
+)
+
+// WriteNTriples writes triples to w in N-Triples format, one line per
+// triple. Triples are written in a deterministic order (sorted by their
+// Hash) rather than Go's randomized map order, so repeated calls against
+// the same triple set produce byte-identical output.
+func WriteNTriples(w io.Writer, triples map[string]*parser.Triple) error {
+	hashes := make([]string, 0, len(triples))
+	for hash := range triples {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	for _, hash := range hashes {
+		triple := triples[hash]
+		subject, err := serializeSubject(triple.Subject)
+		if err != nil {
+			return err
+		}
+		predicate, err := serializePredicate(triple.Predicate)
+		if err != nil {
+			return err
+		}
+		object, err := serializeObject(triple.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s .\n", subject, predicate, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serializeSubject(node *parser.Node) (string, error) {
+	switch node.NodeType {
+	case parser.IRI:
+		return "<" + escapeIRI(node.ID) + ">", nil
+	case parser.BLANK:
+		return "_:" + node.ID, nil
+	default:
+		return "", fmt.Errorf("n-triples: subject must be an IRI or a blank node, got a literal: %q", node.ID)
+	}
+}
+
+func serializePredicate(node *parser.Node) (string, error) {
+	if node.NodeType != parser.IRI {
+		return "", fmt.Errorf("n-triples: predicate must be an IRI, got node type %v", node.NodeType)
+	}
+	return "<" + escapeIRI(node.ID) + ">", nil
+}
+
+func serializeObject(node *parser.Node) (string, error) {
+	switch node.NodeType {
+	case parser.IRI:
+		return "<" + escapeIRI(node.ID) + ">", nil
+	case parser.BLANK:
+		return "_:" + node.ID, nil
+	case parser.LITERAL:
+		return serializeLiteral(node), nil
+	default:
+		return "", fmt.Errorf("n-triples: unknown node type %v", node.NodeType)
+	}
+}
+
+func serializeLiteral(node *parser.Node) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	b.WriteString(escapeLiteral(node.ID))
+	b.WriteByte('"')
+	switch {
+	case node.Lang != "":
+		b.WriteByte('@')
+		b.WriteString(node.Lang)
+	case node.Datatype != "":
+		b.WriteString("^^<")
+		b.WriteString(escapeIRI(node.Datatype))
+		b.WriteByte('>')
+	}
+	return b.String()
+}
+
+// escapeLiteral escapes a literal's lexical form per the N-Triples STRING_LITERAL_QUOTE grammar.
+func escapeLiteral(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeIRI escapes an IRI per the N-Triples IRIREF grammar, which
+// forbids control characters, space, and a handful of reserved
+// delimiters from appearing unescaped between the angle brackets.
+func escapeIRI(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r):
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
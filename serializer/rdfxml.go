@@ -0,0 +1,413 @@
+/**
+ * This module reconstructs a striped RDF/XML document from a Parser's
+ * triples. RDF/XML has no canonical form, so WriteRDFXML doesn't attempt
+ * to reproduce the original document byte-for-byte - only to produce a
+ * valid, readable one that parses back to the same triples.
+ */
+package serializer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/RishabhBhatnagar/gordf/rdfloader/parser"
+)
+
+// SerializerOptions configures WriteRDFXML.
+type SerializerOptions struct {
+	// Prefixes maps a namespace IRI (everything up to and including the
+	// trailing '#' or '/') to the prefix WriteRDFXML should declare and
+	// use for it, e.g. "http://spdx.org/rdf/terms#": "spdx". A namespace
+	// used by the triples but missing from Prefixes is instead assigned
+	// an auto-generated "nsN" prefix, in sorted order of first use.
+	Prefixes map[string]string
+
+	// DetectCollections, when true, recognises the rdf:first/rdf:rest/
+	// rdf:nil linked lists built from blank nodes and writes them back
+	// out as a single rdf:parseType="Collection" property instead of as
+	// a chain of rdf:Description elements joined by rdf:rest.
+	DetectCollections bool
+}
+
+// subjectGroup is every triple sharing one subject, plus the subject node
+// itself (so its NodeType/ID don't need re-deriving from the triples).
+type subjectGroup struct {
+	node    *parser.Node
+	triples []*parser.Triple
+}
+
+func nodeKey(node *parser.Node) string {
+	return fmt.Sprintf("%d:%s", node.NodeType, node.ID)
+}
+
+func groupBySubject(triples map[string]*parser.Triple) (map[string]*subjectGroup, []string) {
+	groups := map[string]*subjectGroup{}
+	for _, triple := range triples {
+		key := nodeKey(triple.Subject)
+		group, ok := groups[key]
+		if !ok {
+			group = &subjectGroup{node: triple.Subject}
+			groups[key] = group
+		}
+		group.triples = append(group.triples, triple)
+	}
+
+	order := make([]string, 0, len(groups))
+	for key := range groups {
+		order = append(order, key)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return groups[order[i]].node.ID < groups[order[j]].node.ID
+	})
+	return groups, order
+}
+
+// isListNode reports whether group is the head of (or a link in) an
+// rdf:parseType="Collection" linked list: a blank node whose only two
+// properties are rdf:first and rdf:rest.
+func isListNode(group *subjectGroup) bool {
+	if group.node.NodeType != parser.BLANK || len(group.triples) != 2 {
+		return false
+	}
+	var hasFirst, hasRest bool
+	for _, triple := range group.triples {
+		switch triple.Predicate.ID {
+		case parser.RDFNS + "first":
+			hasFirst = true
+		case parser.RDFNS + "rest":
+			hasRest = true
+		}
+	}
+	return hasFirst && hasRest
+}
+
+// collectionItems walks the rdf:first/rdf:rest chain starting at head,
+// returning the list's members in order.
+func collectionItems(head *parser.Node, groups map[string]*subjectGroup) ([]*parser.Node, error) {
+	nilIRI := parser.RDFNS + "nil"
+	var items []*parser.Node
+	cur := head
+	for {
+		if cur.NodeType == parser.IRI && cur.ID == nilIRI {
+			return items, nil
+		}
+		group, ok := groups[nodeKey(cur)]
+		if !ok || !isListNode(group) {
+			return nil, fmt.Errorf("rdf/xml: malformed rdf:parseType=\"Collection\" list: %q is not a list node", cur.ID)
+		}
+		var first, rest *parser.Node
+		for _, triple := range group.triples {
+			switch triple.Predicate.ID {
+			case parser.RDFNS + "first":
+				first = triple.Object
+			case parser.RDFNS + "rest":
+				rest = triple.Object
+			}
+		}
+		items = append(items, first)
+		cur = rest
+	}
+}
+
+// nsRegistry assigns a stable prefix to every namespace IRI WriteRDFXML
+// needs to mention, so the same triple set always produces the same
+// xmlns declarations and element names.
+type nsRegistry struct {
+	prefixes map[string]string // namespace IRI -> prefix
+	order    []string          // namespaces, in the order they should be declared
+}
+
+func splitNamespace(iri string) (ns, local string) {
+	if i := strings.LastIndexByte(iri, '#'); i != -1 {
+		return iri[:i+1], iri[i+1:]
+	}
+	if i := strings.LastIndexByte(iri, '/'); i != -1 {
+		return iri[:i+1], iri[i+1:]
+	}
+	return "", iri
+}
+
+// buildNamespaces collects every namespace used by triples' subjects,
+// predicates, and IRI objects, and assigns each one a prefix: the one the
+// caller named in opts.Prefixes if any, otherwise an auto-generated
+// "nsN". Namespaces are visited in sorted order so the numbering is
+// deterministic across runs.
+//
+// The Parser itself doesn't retain namespace bindings once parsing is
+// done - each element's NamespaceScope only lives as long as it takes to
+// walk that element - so there is nothing to "reuse" from p. Deriving
+// prefixes fresh from the triples' own IRIs is the only information
+// actually available after the fact.
+func buildNamespaces(triples map[string]*parser.Triple, opts SerializerOptions) *nsRegistry {
+	seen := map[string]bool{parser.RDFNS: true}
+	var namespaces []string
+
+	collect := func(node *parser.Node) {
+		if node.NodeType != parser.IRI {
+			return
+		}
+		ns, _ := splitNamespace(node.ID)
+		if ns == "" || seen[ns] {
+			return
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	for _, triple := range triples {
+		collect(triple.Subject)
+		collect(triple.Predicate)
+		collect(triple.Object)
+	}
+	sort.Strings(namespaces)
+
+	registry := &nsRegistry{
+		prefixes: map[string]string{parser.RDFNS: "rdf"},
+		order:    []string{parser.RDFNS},
+	}
+	autoIndex := 0
+	for _, ns := range namespaces {
+		if prefix, ok := opts.Prefixes[ns]; ok {
+			registry.prefixes[ns] = prefix
+		} else {
+			registry.prefixes[ns] = fmt.Sprintf("ns%d", autoIndex)
+			autoIndex++
+		}
+		registry.order = append(registry.order, ns)
+	}
+	return registry
+}
+
+// qname renders iri as a prefix:local pair, falling back to the bare
+// local name if iri doesn't split into a known namespace.
+func (registry *nsRegistry) qname(iri string) string {
+	ns, local := splitNamespace(iri)
+	prefix, ok := registry.prefixes[ns]
+	if !ok || prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+// WriteRDFXML writes p's triples to w as a striped RDF/XML document:
+// every subject becomes one element, rdf:type collapses into the element
+// name where a single type is known, and everything else becomes a child
+// element or attribute. When opts.DetectCollections is set, blank-node
+// rdf:first/rdf:rest/rdf:nil lists are written back out using
+// rdf:parseType="Collection" instead of as a chain of rdf:Description
+// elements.
+func WriteRDFXML(w io.Writer, p *parser.Parser, opts SerializerOptions) error {
+	registry := buildNamespaces(p.Triples, opts)
+	groups, order := groupBySubject(p.Triples)
+
+	// A list-shaped blank node (rdf:first + rdf:rest) is only skipped at
+	// the top level if something actually refers to it as an object -
+	// either the owning property or the previous cell's rdf:rest. A blank
+	// node that merely happens to have those two properties but nothing
+	// pointing at it is a normal resource, not a collection cell, and
+	// must still be printed or its triples would silently vanish.
+	consumed := map[string]bool{}
+	if opts.DetectCollections {
+		referenced := map[string]bool{}
+		restTargets := map[string]bool{}
+		for _, triple := range p.Triples {
+			referenced[nodeKey(triple.Object)] = true
+			if triple.Predicate.ID == parser.RDFNS+"rest" {
+				if subjGroup, ok := groups[nodeKey(triple.Subject)]; ok && isListNode(subjGroup) {
+					restTargets[nodeKey(triple.Object)] = true
+				}
+			}
+		}
+		for key, group := range groups {
+			if isListNode(group) && referenced[key] {
+				consumed[key] = true
+			}
+		}
+		// writeCollectionProperty prints each member's own full node
+		// element inline inside the rdf:parseType="Collection" block, so
+		// those member subjects must be consumed too, or they'd also be
+		// printed again as independent top-level elements. Only walk from
+		// heads (list nodes that aren't themselves another cell's
+		// rdf:rest target) so each chain is walked once.
+		for key := range consumed {
+			if restTargets[key] {
+				continue
+			}
+			items, err := collectionItems(groups[key].node, groups)
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				consumed[nodeKey(item)] = true
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>`+"\n<rdf:RDF"); err != nil {
+		return err
+	}
+	for _, ns := range registry.order {
+		if _, err := fmt.Fprintf(w, "\n    xmlns:%s=\"%s\"", registry.prefixes[ns], xmlEscapeAttr(ns)); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, ">\n"); err != nil {
+		return err
+	}
+
+	for _, key := range order {
+		if consumed[key] {
+			continue
+		}
+		if err := writeResourceElement(w, 1, groups[key].node, groups, registry, opts); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "</rdf:RDF>\n")
+	return err
+}
+
+// writeResourceElement writes one node element: an rdf:about/rdf:nodeID
+// subject with one property child per non-rdf:type triple about it. It is
+// also used, recursively, for the member elements of a detected
+// collection, since those are full node elements in their own right.
+func writeResourceElement(w io.Writer, depth int, node *parser.Node, groups map[string]*subjectGroup, registry *nsRegistry, opts SerializerOptions) error {
+	indent := strings.Repeat("  ", depth)
+	group := groups[nodeKey(node)]
+
+	elementName := "rdf:Description"
+	var properties []*parser.Triple
+	typeSeen := false
+	if group != nil {
+		sorted := sortedTriples(group.triples)
+		for _, triple := range sorted {
+			if !typeSeen && triple.Predicate.ID == parser.RDFNS+"type" && triple.Object.NodeType == parser.IRI {
+				elementName = registry.qname(triple.Object.ID)
+				typeSeen = true
+				continue
+			}
+			properties = append(properties, triple)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%s<%s", indent, elementName); err != nil {
+		return err
+	}
+	switch node.NodeType {
+	case parser.IRI:
+		if _, err := fmt.Fprintf(w, " rdf:about=\"%s\"", xmlEscapeAttr(node.ID)); err != nil {
+			return err
+		}
+	case parser.BLANK:
+		if _, err := fmt.Fprintf(w, " rdf:nodeID=\"%s\"", xmlEscapeAttr(node.ID)); err != nil {
+			return err
+		}
+	}
+
+	if len(properties) == 0 {
+		_, err := io.WriteString(w, "/>\n")
+		return err
+	}
+	if _, err := io.WriteString(w, ">\n"); err != nil {
+		return err
+	}
+	for _, triple := range properties {
+		if err := writeProperty(w, depth+1, triple, groups, registry, opts); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s</%s>\n", indent, elementName)
+	return err
+}
+
+// writeProperty writes one property element for triple, choosing between
+// a literal value, an rdf:parseType="Collection", or an rdf:resource/
+// rdf:nodeID reference depending on what triple.Object is.
+func writeProperty(w io.Writer, depth int, triple *parser.Triple, groups map[string]*subjectGroup, registry *nsRegistry, opts SerializerOptions) error {
+	indent := strings.Repeat("  ", depth)
+	name := registry.qname(triple.Predicate.ID)
+
+	if triple.Object.NodeType == parser.LITERAL {
+		var attrs strings.Builder
+		switch {
+		case triple.Object.Datatype != "":
+			fmt.Fprintf(&attrs, " rdf:datatype=\"%s\"", xmlEscapeAttr(triple.Object.Datatype))
+		case triple.Object.Lang != "":
+			fmt.Fprintf(&attrs, " xml:lang=\"%s\"", xmlEscapeAttr(triple.Object.Lang))
+		}
+		_, err := fmt.Fprintf(w, "%s<%s%s>%s</%s>\n", indent, name, attrs.String(), xmlEscapeText(triple.Object.ID), name)
+		return err
+	}
+
+	if opts.DetectCollections {
+		if group, ok := groups[nodeKey(triple.Object)]; ok && isListNode(group) {
+			return writeCollectionProperty(w, depth, name, triple.Object, groups, registry, opts)
+		}
+	}
+
+	switch triple.Object.NodeType {
+	case parser.IRI:
+		_, err := fmt.Fprintf(w, "%s<%s rdf:resource=\"%s\"/>\n", indent, name, xmlEscapeAttr(triple.Object.ID))
+		return err
+	case parser.BLANK:
+		_, err := fmt.Fprintf(w, "%s<%s rdf:nodeID=\"%s\"/>\n", indent, name, xmlEscapeAttr(triple.Object.ID))
+		return err
+	default:
+		return fmt.Errorf("rdf/xml: unknown node type %v for object of %s", triple.Object.NodeType, name)
+	}
+}
+
+func writeCollectionProperty(w io.Writer, depth int, name string, head *parser.Node, groups map[string]*subjectGroup, registry *nsRegistry, opts SerializerOptions) error {
+	indent := strings.Repeat("  ", depth)
+	items, err := collectionItems(head, groups)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		_, err := fmt.Fprintf(w, "%s<%s rdf:parseType=\"Collection\"/>\n", indent, name)
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s<%s rdf:parseType=\"Collection\">\n", indent, name); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writeResourceElement(w, depth+1, item, groups, registry, opts); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "%s</%s>\n", indent, name)
+	return err
+}
+
+// sortedTriples orders a subject's triples deterministically: rdf:type
+// first (so element-name collapsing doesn't depend on map order), then by
+// predicate and object.
+func sortedTriples(triples []*parser.Triple) []*parser.Triple {
+	sorted := make([]*parser.Triple, len(triples))
+	copy(sorted, triples)
+	typeURI := parser.RDFNS + "type"
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iType, jType := sorted[i].Predicate.ID == typeURI, sorted[j].Predicate.ID == typeURI
+		if iType != jType {
+			return iType
+		}
+		if sorted[i].Predicate.ID != sorted[j].Predicate.ID {
+			return sorted[i].Predicate.ID < sorted[j].Predicate.ID
+		}
+		return sorted[i].Object.ID < sorted[j].Object.ID
+	})
+	return sorted
+}
+
+func xmlEscapeText(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(s)
+}
+
+func xmlEscapeAttr(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+	return replacer.Replace(s)
+}
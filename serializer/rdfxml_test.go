@@ -0,0 +1,98 @@
+package serializer
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/RishabhBhatnagar/gordf/rdfloader/parser"
+)
+
+// parseDoc writes doc to a temp file and parses it, failing the test on
+// any error.
+func parseDoc(t *testing.T, doc string) *parser.Parser {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "fixture-*.rdf")
+	if err != nil {
+		t.Fatalf("creating fixture: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(doc); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	p := parser.New()
+	if err := p.Parse(context.Background(), f.Name()); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return p
+}
+
+// TestWriteRDFXML_CollectionMembersAreNotDuplicated is the regression test
+// for the earlier fix to WriteRDFXML's consumed-node bookkeeping: a
+// collection member must be written once, inline inside the
+// rdf:parseType="Collection" block, and not a second time as its own
+// top-level rdf:Description.
+func TestWriteRDFXML_CollectionMembersAreNotDuplicated(t *testing.T) {
+	p := parseDoc(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:about="http://example.org/a">
+  <ex:items rdf:parseType="Collection">
+    <ex:Item rdf:about="http://example.org/i1"/>
+    <ex:Item rdf:about="http://example.org/i2"/>
+  </ex:items>
+</rdf:Description>
+</rdf:RDF>`)
+
+	var out strings.Builder
+	opts := SerializerOptions{DetectCollections: true}
+	if err := WriteRDFXML(&out, p, opts); err != nil {
+		t.Fatalf("WriteRDFXML: %v", err)
+	}
+
+	doc := out.String()
+	for _, member := range []string{"http://example.org/i1", "http://example.org/i2"} {
+		if got := strings.Count(doc, `rdf:about="`+member+`"`); got != 1 {
+			t.Fatalf("member %q appears %d times in output, want 1:\n%s", member, got, doc)
+		}
+	}
+
+	roundTripped := parseDoc(t, doc)
+	firstURI := parser.RDFNS + "first"
+	firstCount := 0
+	for _, triple := range roundTripped.Triples {
+		if triple.Predicate.ID == firstURI {
+			firstCount++
+		}
+	}
+	if firstCount != 2 {
+		t.Fatalf("round-tripped document has %d rdf:first triples, want 2", firstCount)
+	}
+}
+
+// TestWriteRDFXML_NonCollectionBlankNodeIsStillWritten checks that a blank
+// node which merely happens to carry rdf:first and rdf:rest properties, but
+// that nothing else points to, is still printed at the top level rather
+// than being treated as a consumed collection cell.
+func TestWriteRDFXML_NonCollectionBlankNodeIsStillWritten(t *testing.T) {
+	p := parseDoc(t, `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://example.org/ns#">
+<rdf:Description rdf:nodeID="orphan">
+  <rdf:first rdf:resource="http://example.org/x"/>
+  <rdf:rest rdf:resource="http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"/>
+</rdf:Description>
+</rdf:RDF>`)
+
+	var out strings.Builder
+	opts := SerializerOptions{DetectCollections: true}
+	if err := WriteRDFXML(&out, p, opts); err != nil {
+		t.Fatalf("WriteRDFXML: %v", err)
+	}
+
+	doc := out.String()
+	if !strings.Contains(doc, `rdf:nodeID="orphan"`) {
+		t.Fatalf("unreferenced list-shaped blank node was dropped instead of written:\n%s", doc)
+	}
+}